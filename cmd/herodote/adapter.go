@@ -1,13 +1,27 @@
 package main
 
-import "github.com/ViBiOh/herodote/pkg/adapter"
+import (
+	"github.com/ViBiOh/herodote/pkg/adapter"
+	"github.com/ViBiOh/herodote/pkg/store"
+	"github.com/ViBiOh/herodote/pkg/webhook"
+)
 
 type adapters struct {
+	webhook webhook.App
 	adapter adapter.App
 }
 
-func newAdapters(client clients) adapters {
+func newAdapters(config configuration, client clients) adapters {
+	webhookApp := webhook.New(config.webhook, store.New(client.database))
+	go webhookApp.Start()
+
 	return adapters{
-		adapter: adapter.New(client.redis, client.database),
+		webhook: webhookApp,
+		adapter: adapter.New(config.adapter, client.redis, client.database, webhookApp, client.prometheus.Registerer()),
 	}
 }
+
+func (a adapters) Close() {
+	a.adapter.Close()
+	a.webhook.Close()
+}
@@ -9,11 +9,13 @@ import (
 	_ "net/http/pprof"
 
 	"github.com/ViBiOh/herodote/pkg/herodote"
+	"github.com/ViBiOh/herodote/pkg/oidc"
+	"github.com/ViBiOh/herodote/pkg/ratelimit"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
 	"github.com/ViBiOh/httputils/v4/pkg/cors"
 	"github.com/ViBiOh/httputils/v4/pkg/httputils"
 	"github.com/ViBiOh/httputils/v4/pkg/logger"
 	"github.com/ViBiOh/httputils/v4/pkg/owasp"
-	"github.com/ViBiOh/httputils/v4/pkg/prometheus"
 	"github.com/ViBiOh/httputils/v4/pkg/recoverer"
 	"github.com/ViBiOh/httputils/v4/pkg/renderer"
 	"github.com/ViBiOh/httputils/v4/pkg/server"
@@ -41,22 +43,30 @@ func main() {
 
 	defer client.Close(ctx)
 
-	adapter := newAdapters(client)
+	adapter := newAdapters(config, client)
+	defer adapter.Close()
 
 	appServer := server.New(config.appServer)
 	promServer := server.New(config.promServer)
-	prometheusApp := prometheus.New(config.prometheus)
 
-	herodoteApp, err := herodote.New(config.herodote, adapter.adapter, client.tracer.GetTracer("herodote"))
+	vocabularyApp, err := vocabulary.New(config.vocabulary)
+	logger.Fatal(err)
+
+	oidcApp, err := oidc.New(config.oidc)
+	logger.Fatal(err)
+
+	limiterApp := ratelimit.New(config.ratelimit, client.redis)
+
+	herodoteApp, err := herodote.New(config.herodote, adapter.adapter, vocabularyApp, oidcApp, limiterApp, client.health.Done(ctx).Done(), client.tracer.GetTracer("herodote"))
 	logger.Fatal(err)
 
 	rendererApp, err := renderer.New(config.renderer, content, herodote.FuncMap, client.tracer.GetTracer("renderer"))
 	logger.Fatal(err)
 
-	rendererHandler := rendererApp.Handler(herodoteApp.TemplateFunc)
+	rendererHandler := herodote.PageMiddleware(rendererApp.Handler(herodoteApp.TemplateFunc))
 
-	go promServer.Start(client.health.End(ctx), "prometheus", prometheusApp.Handler())
-	go appServer.Start(client.health.End(ctx), "http", httputils.Handler(rendererHandler, client.health, recoverer.Middleware, prometheusApp.Middleware, client.tracer.Middleware, owasp.New(config.owasp).Middleware, cors.New(config.cors).Middleware))
+	go promServer.Start(client.health.End(ctx), "prometheus", client.prometheus.Handler())
+	go appServer.Start(client.health.End(ctx), "http", httputils.Handler(rendererHandler, client.health, recoverer.Middleware, client.prometheus.Middleware, client.tracer.Middleware, owasp.New(config.owasp).Middleware, cors.New(config.cors).Middleware))
 
 	client.health.WaitForTermination(appServer.Done())
 	server.GracefulWait(appServer.Done(), promServer.Done())
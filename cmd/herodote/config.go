@@ -6,7 +6,12 @@ import (
 	"time"
 
 	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/herodote/pkg/adapter"
 	"github.com/ViBiOh/herodote/pkg/herodote"
+	"github.com/ViBiOh/herodote/pkg/oidc"
+	"github.com/ViBiOh/herodote/pkg/ratelimit"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
+	"github.com/ViBiOh/herodote/pkg/webhook"
 	"github.com/ViBiOh/httputils/v4/pkg/alcotest"
 	"github.com/ViBiOh/httputils/v4/pkg/cors"
 	"github.com/ViBiOh/httputils/v4/pkg/db"
@@ -32,6 +37,11 @@ type configuration struct {
 	cors       cors.Config
 	renderer   renderer.Config
 	herodote   herodote.Config
+	oidc       oidc.Config
+	ratelimit  ratelimit.Config
+	adapter    adapter.Config
+	vocabulary vocabulary.Config
+	webhook    webhook.Config
 	db         db.Config
 	redis      redis.Config
 }
@@ -52,6 +62,11 @@ func newConfig() (configuration, error) {
 		cors:       cors.Flags(fs, "cors"),
 		renderer:   renderer.Flags(fs, "", flags.NewOverride("Title", "Herodote"), flags.NewOverride("PublicURL", "https://herodote.vibioh.fr")),
 		herodote:   herodote.Flags(fs, ""),
+		oidc:       oidc.Flags(fs, "oidc"),
+		ratelimit:  ratelimit.Flags(fs, "ratelimit"),
+		adapter:    adapter.Flags(fs, "cache"),
+		vocabulary: vocabulary.Flags(fs, ""),
+		webhook:    webhook.Flags(fs, "webhook"),
 		db:         db.Flags(fs, "db"),
 		redis:      redis.Flags(fs, "redis"),
 	}, fs.Parse(os.Args[1:])
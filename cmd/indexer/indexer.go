@@ -2,23 +2,52 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/herodote/pkg/adapter"
+	"github.com/ViBiOh/herodote/pkg/digest"
+	"github.com/ViBiOh/herodote/pkg/email"
+	"github.com/ViBiOh/herodote/pkg/importer"
 	"github.com/ViBiOh/herodote/pkg/store"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
 	"github.com/ViBiOh/httputils/v4/pkg/db"
 	"github.com/ViBiOh/httputils/v4/pkg/logger"
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+)
+
+const (
+	refreshMode = "refresh"
+	digestMode  = "digest"
+	emailMode   = "email"
+	importMode  = "import"
+	gitMode     = "import-git"
+	auditMode   = "audit"
 )
 
 func main() {
+	mode, args := parseMode(os.Args[1:])
+
 	fs := flag.NewFlagSet("indexer", flag.ExitOnError)
 	fs.Usage = flags.Usage(fs)
 
 	loggerConfig := logger.Flags(fs, "logger")
 	dbConfig := db.Flags(fs, "db")
+	redisConfig := redis.Flags(fs, "redis")
+	cacheConfig := adapter.Flags(fs, "cache")
+	digestConfig := digest.Flags(fs, "digest")
+	emailConfig := email.Flags(fs, "email")
+	importConfig := importer.Flags(fs, "import")
+	gitConfig := importer.GitFlags(fs, "")
+	vocabularyConfig := vocabulary.Flags(fs, "")
+	auditRetention := flags.New("AuditRetention", "Duration audit entries are kept, older ones are purged by the audit mode").Prefix("").DocPrefix("audit").Duration(fs, time.Hour*24*90, nil)
 
-	logger.Fatal(fs.Parse(os.Args[1:]))
+	logger.Fatal(fs.Parse(args))
 
 	logger.Global(logger.New(loggerConfig))
 	defer logger.Close()
@@ -29,7 +58,121 @@ func main() {
 	logger.Fatal(err)
 	defer herodoteDb.Close()
 
-	logger.Info("Lexeme refresh...")
-	logger.Fatal(store.New(herodoteDb).Refresh(ctx))
-	logger.Info("Lexeme refreshed!")
+	storeApp := store.New(herodoteDb)
+
+	switch mode {
+	case refreshMode:
+		logger.Info("Lexeme refresh...")
+		logger.Fatal(storeApp.Refresh(ctx))
+		logger.Info("Lexeme refreshed!")
+
+	case digestMode:
+		digestApp, err := digest.New(digestConfig, storeApp)
+		logger.Fatal(err)
+
+		logger.Info("Digest sending...")
+		logger.Fatal(digestApp.Send(ctx))
+		logger.Info("Digest sent!")
+
+	case emailMode:
+		vocabularyApp, err := vocabulary.New(vocabularyConfig)
+		logger.Fatal(err)
+
+		emailApp, err := email.New(emailConfig, storeApp, vocabularyApp)
+		logger.Fatal(err)
+
+		logger.Info("Emails sending...")
+		logger.Fatal(emailApp.Send(ctx))
+		logger.Info("Emails sent!")
+
+	case importMode, gitMode:
+		vocabularyApp, err := vocabulary.New(vocabularyConfig)
+		logger.Fatal(err)
+
+		// imported commits go through the adapter for aliases and cache, without notifying webhooks
+		redisClient, err := redis.New(redisConfig, nil)
+		logger.Fatal(err)
+		defer redisClient.Close()
+
+		adapterApp := adapter.New(cacheConfig, redisClient, herodoteDb, nil, nil)
+		defer adapterApp.Close()
+
+		importApp, err := importer.New(importConfig, adapterApp, vocabularyApp)
+		logger.Fatal(err)
+
+		if mode == importMode {
+			logger.Fatal(importDump(ctx, importApp, storeApp, fs.Arg(0)))
+			break
+		}
+
+		source, err := importer.NewGitSource(ctx, gitConfig)
+		logger.Fatal(err)
+
+		logger.Info("Import of `%s` from `%s`...", source.Repository, source.Path)
+
+		report, err := importApp.ImportGit(ctx, source)
+		logger.Info("Import done: %s", report)
+
+		logger.Fatal(refreshAfterImport(ctx, importApp, storeApp, report, err))
+
+	case auditMode:
+		logger.Info("Audit purge...")
+
+		count, err := storeApp.PurgeAudit(ctx, time.Now().Add(-*auditRetention))
+		logger.Fatal(err)
+
+		logger.Info("Audit purged, %d entries deleted!", count)
+
+	default:
+		logger.Fatal(fmt.Errorf("unknown mode `%s`, expected one of `%s`, `%s`, `%s`, `%s`, `%s`, `%s`", mode, refreshMode, digestMode, emailMode, importMode, gitMode, auditMode))
+	}
+}
+
+// importDump imports the given file, or the standard input if empty or `-`, then refreshes search filters
+func importDump(ctx context.Context, importApp importer.App, storeApp store.App, filename string) error {
+	reader := os.Stdin
+
+	if len(filename) != 0 && filename != "-" {
+		file, err := os.Open(filename)
+		if err != nil {
+			return fmt.Errorf("open dump: %w", err)
+		}
+
+		defer file.Close()
+
+		reader = file
+	}
+
+	if importApp.DryRun() {
+		logger.Info("Import dry-run of `%s`...", filename)
+	} else {
+		logger.Info("Import of `%s`...", filename)
+	}
+
+	report, err := importApp.Import(ctx, reader, importApp.Format(filename))
+	logger.Info("Import done: %s", report)
+
+	return refreshAfterImport(ctx, importApp, storeApp, report, err)
+}
+
+// refreshAfterImport refreshes search filters if commits were saved, even if the import failed after some batches
+func refreshAfterImport(ctx context.Context, importApp importer.App, storeApp store.App, report importer.Report, err error) error {
+	if importApp.DryRun() || report.Created+report.Updated == 0 {
+		return err
+	}
+
+	if refreshErr := storeApp.Refresh(ctx); refreshErr != nil {
+		return errors.Join(err, fmt.Errorf("refresh: %w", refreshErr))
+	}
+
+	return err
+}
+
+// parseMode extracts the optional mode given as first argument, e.g. `indexer digest -digestURL ...`
+func parseMode(args []string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return refreshMode, args
+	}
+
+	return args[0], args[1:]
 }
@@ -33,8 +33,39 @@ var_color() {
   export RESET='\033[0m'
 }
 
+api_conventionnal_commits() {
+  HTTP_STATUS="$(curl --disable --silent --show-error --location --max-time 10 \
+    -o "${HTTP_OUTPUT}" \
+    -w "%{http_code}" \
+    "${HERODOTE_API}/api/types")"
+
+  if [[ ${HTTP_STATUS} -ne 200 ]]; then
+    printf "%bunable to get types from backend, using default ones%b\n" "${YELLOW}" "${RESET}" 1>&2
+    rm "${HTTP_OUTPUT}"
+    return 1
+  fi
+
+  local TYPE
+  local DESCRIPTION
+  local PRODUCTION
+  while IFS=$'\t' read -r TYPE PRODUCTION DESCRIPTION; do
+    if [[ ${PRODUCTION} == "true" ]]; then
+      DESCRIPTION="$(printf '%s %b(production change)%b' "${DESCRIPTION}" "${RED}" "${RESET}")"
+    fi
+
+    CONVENTIONAL_COMMIT_SCOPES["${TYPE}"]="${DESCRIPTION}"
+  done < <(jq --raw-output '.[] | .description as $description | (.production // false) as $production | (.name, (.aliases // [])[]) | [., $production, $description] | @tsv' "${HTTP_OUTPUT}")
+
+  rm "${HTTP_OUTPUT}"
+}
+
 git_conventionnal_commits() {
   declare -gA CONVENTIONAL_COMMIT_SCOPES
+
+  if api_conventionnal_commits; then
+    return
+  fi
+
   CONVENTIONAL_COMMIT_SCOPES['build']='Changes that affect the build system or external dependencies'
   CONVENTIONAL_COMMIT_SCOPES['chore']='Changes in the core of the repository'
   CONVENTIONAL_COMMIT_SCOPES['ci']='Changes in Continuous Integration configuration files and scripts'
@@ -145,6 +176,13 @@ walk_log() {
   local COMMITS
   COMMITS="$(git log --pretty=format:'%h' "$(latest_commit)")"
 
+  local BRANCHES="[]"
+  local BRANCH
+  BRANCH="$(git rev-parse --abbrev-ref HEAD)"
+  if [[ ${BRANCH} != "HEAD" ]]; then
+    BRANCHES="$(jq -c -n --arg branch "${BRANCH}" '[$branch]')"
+  fi
+
   shopt -s nocasematch
   for hash in ${COMMITS}; do
     if [[ $(git show -s --format='%h %aI %s' "${hash}") =~ ^([0-9a-f]{1,16})\ ([^\ ]+)\ (.*)$ ]]; then
@@ -187,6 +225,12 @@ walk_log() {
 
       count="$((count + 1))"
 
+      local PATHS
+      PATHS="$(git show --name-only --format='' "${hash}" | jq --raw-input --slurp --compact-output 'split("\n") | map(select(length > 0))')"
+
+      local PICKED_FROM
+      PICKED_FROM="$(git show -s --format='%b' "${hash}" | sed -n 's/^(cherry picked from commit \([0-9a-f]*\))$/\1/p' | head -1)"
+
       local PAYLOAD
       PAYLOAD="$(
         jq -c -n \
@@ -199,6 +243,9 @@ walk_log() {
           --arg date "${DATE}" \
           --arg remote "${GIT_HOST}" \
           --arg repository "${GIT_REPOSITORY}" \
+          --argjson paths "${PATHS}" \
+          --argjson branches "${BRANCHES}" \
+          --arg pickedFrom "${PICKED_FROM}" \
           '{
           "hash": $hash,
           "type": $type,
@@ -208,7 +255,10 @@ walk_log() {
           "content": $content,
           "date": $date,
           "remote": $remote,
-          "repository": $repository
+          "repository": $repository,
+          "paths": $paths,
+          "branches": $branches,
+          "pickedFrom": $pickedFrom
         }'
       )"
 
@@ -2,59 +2,189 @@ package adapter
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"time"
 
+	"github.com/ViBiOh/flags"
 	"github.com/ViBiOh/herodote/pkg/model"
 	"github.com/ViBiOh/herodote/pkg/store"
-	"github.com/ViBiOh/herodote/pkg/version"
-	"github.com/ViBiOh/httputils/v4/pkg/cache"
-	"github.com/ViBiOh/httputils/v4/pkg/cntxt"
 	"github.com/ViBiOh/httputils/v4/pkg/db"
-	"github.com/ViBiOh/httputils/v4/pkg/logger"
 	"github.com/ViBiOh/httputils/v4/pkg/redis"
 	"github.com/ViBiOh/httputils/v4/pkg/sha"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+const (
+	cacheTTL           = time.Hour
+	invalidationWindow = time.Second * 2
+)
+
+// Notifier is notified of saved commits
+type Notifier interface {
+	Notify(model.Commit)
+	Expire()
+}
+
 type App struct {
-	redis redis.Client
-	store store.App
+	cache         searchCache
+	notifier      Notifier
+	invalidator   *invalidator
+	aliases       *aliases
+	privates      *privates
+	stream        *stream
+	importCommits func(context.Context, []model.Commit) (uint64, uint64, error)
+	store         store.App
+}
+
+type Config struct {
+	memorySize *uint
+	memoryTTL  *time.Duration
+}
+
+func Flags(fs *flag.FlagSet, prefix string) Config {
+	return Config{
+		memorySize: flags.New("MemorySize", "In-memory cache max entries when Redis is disabled, 0 to disable").Prefix(prefix).DocPrefix("cache").Uint(fs, 500, nil),
+		memoryTTL:  flags.New("MemoryTTL", "In-memory cache entries TTL").Prefix(prefix).DocPrefix("cache").Duration(fs, time.Minute*10, nil),
+	}
 }
 
-func New(redis redis.Client, database db.App) App {
-	return App{
-		redis: redis,
-		store: store.New(database),
+func New(config Config, redis redis.Client, database db.App, notifier Notifier, prometheusRegisterer prometheus.Registerer) App {
+	app := App{
+		store:    store.New(database),
+		aliases:  &aliases{},
+		privates: &privates{},
+		notifier: notifier,
+		stream:   newStream(redis),
+	}
+
+	if redis.Enabled() {
+		app.cache = newRedisCache(redis, cacheTTL, prometheusRegisterer)
+	} else {
+		app.cache = newMemoryCache(*config.memorySize, *config.memoryTTL, prometheusRegisterer)
 	}
+
+	app.invalidator = newInvalidator(invalidationWindow, app.evict)
+	app.importCommits = app.store.ImportCommits
+
+	return app
 }
 
 func (a App) Enabled() bool {
 	return a.store.Enabled()
 }
 
-func (a App) ListFilters(ctx context.Context) (map[string][]string, error) {
-	return a.store.ListFilters(ctx)
+func (a App) Close() {
+	a.invalidator.Close()
+	a.stream.close()
+}
+
+func (a App) ListFilters(ctx context.Context, visibility []string) (map[string][]string, error) {
+	return a.store.ListFilters(ctx, visibility)
+}
+
+// LastModified returns the time of the last change of data served by SearchCommit
+func (a App) LastModified(ctx context.Context) (time.Time, error) {
+	return a.cache.LastModified(ctx)
 }
 
 func (a App) SearchCommit(ctx context.Context, query string, filters map[string][]string, before, after string, pageSize uint, last string) (model.CommitsList, error) {
+	filters = a.resolveFilters(ctx, filters)
 	searchHash := sha.Stream().Write(query).Write(filters).Write(before).Write(after).Write(pageSize).Write(last).Sum()
 
-	return cache.Load(ctx, a.redis, version.Redis("commits:"+searchHash), func(ctx context.Context) (model.CommitsList, error) {
+	return a.cache.Load(ctx, "commits:"+searchHash, cacheTags(filters["repository"]), func(ctx context.Context) (model.CommitsList, error) {
 		return a.store.SearchCommit(ctx, query, filters, before, after, pageSize, last)
-	}, time.Hour)
+	})
+}
+
+// ExportCommits streams every commit matching the search to the handler, bypassing the cache
+func (a App) ExportCommits(ctx context.Context, query string, filters map[string][]string, before, after string, handler func(model.CommitEvent) error) error {
+	return a.store.ExportCommits(ctx, query, a.resolveFilters(ctx, filters), before, after, handler)
+}
+
+func (a App) SaveCommit(ctx context.Context, commit model.Commit) (bool, error) {
+	commit.Repository = a.resolveRepository(ctx, commit.Repository)
+
+	id, err := a.store.SaveCommit(ctx, commit)
+	if err != nil {
+		return false, fmt.Errorf("save: %w", err)
+	}
+
+	a.invalidator.Add(commit.Repository)
+
+	// an existing commit only got new branches, it's not announced again
+	if id == 0 {
+		return false, nil
+	}
+
+	a.stream.publish(ctx, model.CommitEvent{ID: id, Commit: commit})
+
+	if a.notifier != nil {
+		a.notifier.Notify(commit)
+	}
+
+	return true, nil
+}
+
+// StreamCommits returns a channel of saved commits, the returned function unsubscribes
+func (a App) StreamCommits() (<-chan model.CommitEvent, func()) {
+	return a.stream.subscribe()
+}
+
+// ListCommitEvents returns commits saved after the given event id, to resume a stream
+func (a App) ListCommitEvents(ctx context.Context, after uint64, pageSize uint) ([]model.CommitEvent, error) {
+	return a.store.ListCommitEvents(ctx, after, pageSize)
+}
+
+func (a App) GetCommit(ctx context.Context, repository, hash string) (model.Commit, error) {
+	return a.store.GetCommit(ctx, a.resolveRepository(ctx, repository), hash)
+}
+
+func (a App) FindCommit(ctx context.Context, repository, hash string) (model.Commit, error) {
+	return a.store.FindCommit(ctx, a.resolveRepository(ctx, repository), hash)
+}
+
+func (a App) UpdateCommit(ctx context.Context, repository, hash string, commit model.Commit) error {
+	commit.Repository = a.resolveRepository(ctx, commit.Repository)
+
+	if err := a.store.UpdateCommit(ctx, repository, hash, commit); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	a.invalidator.Add(repository, commit.Repository)
+
+	return a.refreshFilters(ctx)
+}
+
+func (a App) DeleteCommit(ctx context.Context, repository, hash string) error {
+	repository = a.resolveRepository(ctx, repository)
+
+	if err := a.store.DeleteCommit(ctx, repository, hash); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	a.invalidator.Add(repository)
+
+	return a.refreshFilters(ctx)
 }
 
-func (a App) SaveCommit(ctx context.Context, commit model.Commit) error {
-	err := a.store.SaveCommit(ctx, commit)
+func (a App) DeleteRepository(ctx context.Context, repository string) (uint64, error) {
+	repository = a.resolveRepository(ctx, repository)
+
+	count, err := a.store.DeleteRepository(ctx, repository)
 	if err != nil {
-		return fmt.Errorf("save: %w", err)
+		return count, fmt.Errorf("delete: %w", err)
 	}
 
-	go func(ctx context.Context) {
-		if err := a.redis.DeletePattern(ctx, version.Redis("commits:*")); err != nil {
-			logger.Error("redis delete after save commit: %s", err)
-		}
-	}(cntxt.WithoutDeadline(ctx))
+	a.invalidator.Add(repository)
+
+	return count, a.refreshFilters(ctx)
+}
+
+func (a App) refreshFilters(ctx context.Context) error {
+	if err := a.store.Refresh(ctx); err != nil {
+		return fmt.Errorf("refresh filters: %w", err)
+	}
 
-	return err
+	return nil
 }
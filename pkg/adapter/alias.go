@@ -0,0 +1,118 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+)
+
+const aliasesTTL = time.Minute
+
+// aliases keeps in memory the repositories' aliases, reloaded periodically to catch changes of other instances
+type aliases struct {
+	expiration time.Time
+	values     map[string]string
+	mutex      sync.RWMutex
+}
+
+func (a *aliases) get(repository string) (string, bool) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	if time.Now().After(a.expiration) {
+		return "", false
+	}
+
+	if target, ok := a.values[repository]; ok {
+		return target, true
+	}
+
+	return repository, true
+}
+
+func (a *aliases) set(list []model.RepositoryAlias) {
+	values := make(map[string]string, len(list))
+	for _, alias := range list {
+		values[alias.Alias] = alias.Repository
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.values = values
+	a.expiration = time.Now().Add(aliasesTTL)
+}
+
+func (a *aliases) expire() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.expiration = time.Time{}
+}
+
+// resolveRepository returns the current name of a repository
+func (a App) resolveRepository(ctx context.Context, repository string) string {
+	if target, ok := a.aliases.get(repository); ok {
+		return target
+	}
+
+	list, err := a.store.ListAliases(ctx)
+	if err != nil {
+		logger.Error("list aliases: %s", err)
+		return repository
+	}
+
+	a.aliases.set(list)
+
+	target, _ := a.aliases.get(repository)
+
+	return target
+}
+
+func (a App) resolveFilters(ctx context.Context, filters map[string][]string) map[string][]string {
+	repositories := filters["repository"]
+	if len(repositories) == 0 {
+		return filters
+	}
+
+	output := make(map[string][]string, len(filters))
+	for key, values := range filters {
+		output[key] = values
+	}
+
+	resolved := make([]string, len(repositories))
+	for index, repository := range repositories {
+		resolved[index] = a.resolveRepository(ctx, strings.ToLower(strings.TrimSpace(repository)))
+	}
+
+	output["repository"] = resolved
+
+	return output
+}
+
+func (a App) ListAliases(ctx context.Context) ([]model.RepositoryAlias, error) {
+	return a.store.ListAliases(ctx)
+}
+
+func (a App) RenameRepository(ctx context.Context, alias model.RepositoryAlias) (uint64, error) {
+	// the new name may itself be a former name, unless we are renaming back
+	if target := a.resolveRepository(ctx, alias.Repository); target != alias.Alias {
+		alias.Repository = target
+	}
+
+	count, err := a.store.RenameRepository(ctx, alias)
+	if err != nil {
+		return count, fmt.Errorf("rename: %w", err)
+	}
+
+	a.aliases.expire()
+	a.privates.expire()
+	a.invalidator.Add(alias.Alias, alias.Repository)
+
+	return count, a.refreshFilters(ctx)
+}
@@ -0,0 +1,24 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+func (a App) SaveAudit(ctx context.Context, entry model.AuditEntry) error {
+	return a.store.SaveAudit(ctx, entry)
+}
+
+func (a App) ListAudit(ctx context.Context, filter model.AuditFilter, pageSize uint, last uint64) ([]model.AuditEntry, uint, error) {
+	if len(filter.Repository) != 0 {
+		filter.Repository = a.resolveRepository(ctx, filter.Repository)
+	}
+
+	return a.store.ListAudit(ctx, filter, pageSize, last)
+}
+
+func (a App) PurgeAudit(ctx context.Context, before time.Time) (uint64, error) {
+	return a.store.PurgeAudit(ctx, before)
+}
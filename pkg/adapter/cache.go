@@ -0,0 +1,180 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/version"
+	"github.com/ViBiOh/httputils/v4/pkg/cache"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+	httpPrometheus "github.com/ViBiOh/httputils/v4/pkg/prometheus"
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	goRedis "github.com/redis/go-redis/v9"
+)
+
+const (
+	metricsNamespace = "herodote"
+	metricsSubsystem = "cache"
+
+	lastModifiedTTL = time.Hour * 24 * 30
+)
+
+type searchCache interface {
+	Load(ctx context.Context, key string, tags []string, onMiss func(context.Context) (model.CommitsList, error)) (model.CommitsList, error)
+	Evict(ctx context.Context, tags []string) error
+	LastModified(ctx context.Context) (time.Time, error)
+}
+
+type metrics struct {
+	hits      *prometheus.CounterVec
+	misses    *prometheus.CounterVec
+	evictions *prometheus.CounterVec
+	backend   string
+}
+
+func newMetrics(prometheusRegisterer prometheus.Registerer, backend string) metrics {
+	return metrics{
+		backend:   backend,
+		hits:      httpPrometheus.CounterVec(prometheusRegisterer, metricsNamespace, metricsSubsystem, "hits", "backend"),
+		misses:    httpPrometheus.CounterVec(prometheusRegisterer, metricsNamespace, metricsSubsystem, "misses", "backend"),
+		evictions: httpPrometheus.CounterVec(prometheusRegisterer, metricsNamespace, metricsSubsystem, "evictions", "backend"),
+	}
+}
+
+func (m metrics) hit() {
+	if m.hits != nil {
+		m.hits.WithLabelValues(m.backend).Inc()
+	}
+}
+
+func (m metrics) miss() {
+	if m.misses != nil {
+		m.misses.WithLabelValues(m.backend).Inc()
+	}
+}
+
+func (m metrics) evict(count int) {
+	if m.evictions != nil {
+		m.evictions.WithLabelValues(m.backend).Add(float64(count))
+	}
+}
+
+type redisCache struct {
+	client  redis.Client
+	metrics metrics
+	ttl     time.Duration
+}
+
+func newRedisCache(client redis.Client, ttl time.Duration, prometheusRegisterer prometheus.Registerer) redisCache {
+	return redisCache{
+		client:  client,
+		ttl:     ttl,
+		metrics: newMetrics(prometheusRegisterer, "redis"),
+	}
+}
+
+var lastModifiedKey = version.Redis("last-modified")
+
+func redisTag(tag string) string {
+	return version.Redis("tags:" + tag)
+}
+
+func (r redisCache) Load(ctx context.Context, key string, tags []string, onMiss func(context.Context) (model.CommitsList, error)) (model.CommitsList, error) {
+	key = version.Redis(key)
+	missed := false
+
+	output, err := cache.Load(ctx, r.client, key, func(ctx context.Context) (model.CommitsList, error) {
+		missed = true
+		r.tag(ctx, key, tags)
+
+		return onMiss(ctx)
+	}, r.ttl)
+
+	if missed {
+		r.metrics.miss()
+	} else {
+		r.metrics.hit()
+	}
+
+	return output, err
+}
+
+func (r redisCache) tag(ctx context.Context, key string, tags []string) {
+	pipeline := r.client.Pipeline()
+
+	for _, tag := range tags {
+		pipeline.SAdd(ctx, redisTag(tag), key)
+		pipeline.Expire(ctx, redisTag(tag), r.ttl)
+	}
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		logger.WithField("key", key).Error("tag cache key: %s", err)
+	}
+}
+
+func (r redisCache) Evict(ctx context.Context, tags []string) error {
+	pipeline := r.client.Pipeline()
+
+	members := make([]*goRedis.StringSliceCmd, len(tags))
+	keys := make([]string, len(tags))
+
+	for index, tag := range tags {
+		keys[index] = redisTag(tag)
+		members[index] = pipeline.SMembers(ctx, keys[index])
+	}
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return fmt.Errorf("list tagged keys: %w", err)
+	}
+
+	evicted := make(map[string]struct{})
+	for _, member := range members {
+		for _, key := range member.Val() {
+			evicted[key] = struct{}{}
+		}
+	}
+
+	for key := range evicted {
+		keys = append(keys, key)
+	}
+
+	if err := r.client.Delete(ctx, keys...); err != nil {
+		return fmt.Errorf("delete tagged keys: %w", err)
+	}
+
+	r.metrics.evict(len(evicted))
+
+	if err := r.client.Store(ctx, lastModifiedKey, time.Now().UnixNano(), lastModifiedTTL); err != nil {
+		return fmt.Errorf("store last modified: %w", err)
+	}
+
+	return nil
+}
+
+func (r redisCache) LastModified(ctx context.Context) (time.Time, error) {
+	content, err := r.client.Load(ctx, lastModifiedKey)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("load last modified: %w", err)
+	}
+
+	if len(content) == 0 {
+		now := time.Now()
+
+		if err = r.client.Store(ctx, lastModifiedKey, now.UnixNano(), lastModifiedTTL); err != nil {
+			return time.Time{}, fmt.Errorf("init last modified: %w", err)
+		}
+
+		return now, nil
+	}
+
+	nanos, err := strconv.ParseInt(string(content), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse last modified: %w", err)
+	}
+
+	return time.Unix(0, nanos), nil
+}
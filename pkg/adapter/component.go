@@ -0,0 +1,21 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+)
+
+func (a App) RewriteComponent(ctx context.Context, from, to string) (uint64, error) {
+	count, repositories, err := a.store.RewriteComponent(ctx, from, to)
+	if err != nil {
+		return count, fmt.Errorf("rewrite: %w", err)
+	}
+
+	if count == 0 {
+		return count, nil
+	}
+
+	a.invalidator.Add(repositories...)
+
+	return count, a.refreshFilters(ctx)
+}
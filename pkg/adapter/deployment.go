@@ -0,0 +1,39 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+func (a App) CreateDeployment(ctx context.Context, deployment model.Deployment) (uint64, error) {
+	deployment.Repository = a.resolveRepository(ctx, deployment.Repository)
+
+	id, err := a.store.CreateDeployment(ctx, deployment)
+	if err != nil {
+		return id, fmt.Errorf("create: %w", err)
+	}
+
+	// the timeline displays deployments, its pages change
+	a.invalidator.Add(deployment.Repository)
+
+	return id, nil
+}
+
+func (a App) ListDeployments(ctx context.Context, repository, environment, status string, visibility []string, pageSize uint) ([]model.Deployment, error) {
+	if len(repository) != 0 {
+		repository = a.resolveRepository(ctx, repository)
+	}
+
+	return a.store.ListDeployments(ctx, repository, environment, status, visibility, pageSize)
+}
+
+func (a App) ListCurrentDeployments(ctx context.Context, repositories []string) ([]model.Deployment, error) {
+	resolved := make([]string, len(repositories))
+	for index, repository := range repositories {
+		resolved[index] = a.resolveRepository(ctx, repository)
+	}
+
+	return a.store.ListCurrentDeployments(ctx, resolved)
+}
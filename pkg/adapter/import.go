@@ -0,0 +1,38 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+// ImportCommits saves commits of a dump under the current name of their repository and evicts their cached searches.
+// Imports are backfills of history: commits aren't announced to the stream nor to webhooks.
+func (a App) ImportCommits(ctx context.Context, commits []model.Commit) (uint64, uint64, error) {
+	resolved := make([]model.Commit, len(commits))
+	repositories := make(map[string]struct{})
+
+	for index, commit := range commits {
+		commit.Repository = a.resolveRepository(ctx, commit.Repository)
+
+		resolved[index] = commit
+		repositories[commit.Repository] = struct{}{}
+	}
+
+	created, updated, err := a.importCommits(ctx, resolved)
+	if err != nil {
+		return created, updated, fmt.Errorf("import: %w", err)
+	}
+
+	for repository := range repositories {
+		a.invalidator.Add(repository)
+	}
+
+	return created, updated, nil
+}
+
+// ListCommitBranches returns the branches of every saved commit of the repository, by hash
+func (a App) ListCommitBranches(ctx context.Context, repository string) (map[string][]string, error) {
+	return a.store.ListCommitBranches(ctx, a.resolveRepository(ctx, repository))
+}
@@ -0,0 +1,100 @@
+package adapter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+)
+
+// recordNotifier keeps the notified commits
+type recordNotifier struct {
+	commits []model.Commit
+}
+
+func (n *recordNotifier) Notify(commit model.Commit) {
+	n.commits = append(n.commits, commit)
+}
+
+func (n *recordNotifier) Expire() {}
+
+func TestImportCommits(t *testing.T) {
+	cases := map[string]struct {
+		commits          []model.Commit
+		wantRepositories []string
+		wantEvicted      []string
+	}{
+		"created": {
+			[]model.Commit{{Hash: "1a2bc34d", Repository: "vibioh/herodote"}},
+			[]string{"vibioh/herodote"},
+			[]string{"vibioh/herodote"},
+		},
+		"alias": {
+			[]model.Commit{{Hash: "1a2bc34d", Repository: "vibioh/herodot"}, {Hash: "5e6f", Repository: "vibioh/ketchup"}},
+			[]string{"vibioh/herodote", "vibioh/ketchup"},
+			[]string{"vibioh/herodote", "vibioh/ketchup"},
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			var saved []model.Commit
+			var evicted []string
+
+			notifier := &recordNotifier{}
+
+			instance := App{
+				notifier: notifier,
+				aliases:  &aliases{},
+				stream:   newStream(redis.Noop{}),
+				importCommits: func(_ context.Context, commits []model.Commit) (uint64, uint64, error) {
+					saved = commits
+					return uint64(len(commits)), 0, nil
+				},
+				invalidator: newInvalidator(time.Hour, func(_ context.Context, repositories []string) error {
+					evicted = repositories
+					return nil
+				}),
+			}
+			instance.aliases.set([]model.RepositoryAlias{{Alias: "vibioh/herodot", Repository: "vibioh/herodote"}})
+
+			events, unsubscribe := instance.stream.subscribe()
+			defer unsubscribe()
+
+			created, _, err := instance.ImportCommits(context.Background(), tc.commits)
+			if err != nil {
+				t.Fatalf("ImportCommits() = `%s`", err)
+			}
+
+			instance.invalidator.Close()
+
+			if created != uint64(len(tc.commits)) {
+				t.Errorf("ImportCommits() created = %d, want %d", created, len(tc.commits))
+			}
+
+			var gotRepositories []string
+			for _, commit := range saved {
+				gotRepositories = append(gotRepositories, commit.Repository)
+			}
+
+			if !reflect.DeepEqual(gotRepositories, tc.wantRepositories) {
+				t.Errorf("ImportCommits() saved %v, want %v", gotRepositories, tc.wantRepositories)
+			}
+
+			if !reflect.DeepEqual(evicted, tc.wantEvicted) {
+				t.Errorf("ImportCommits() evicted %v, want %v", evicted, tc.wantEvicted)
+			}
+
+			if len(notifier.commits) != 0 {
+				t.Errorf("ImportCommits() notified webhooks of %d commits", len(notifier.commits))
+			}
+
+			if ids, _ := receivedIDs(events); len(ids) != 0 {
+				t.Errorf("ImportCommits() streamed %v", ids)
+			}
+		})
+	}
+}
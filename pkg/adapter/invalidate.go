@@ -0,0 +1,114 @@
+package adapter
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+)
+
+const (
+	flushTimeout = time.Second * 10
+	allTag       = "all"
+)
+
+func repositoryTag(repository string) string {
+	return "repository:" + repository
+}
+
+// cacheTags returns the tags of a search covering given repositories, a search without repository covers all of them
+func cacheTags(repositories []string) []string {
+	var tags []string
+
+	for _, repository := range repositories {
+		if repository = strings.ToLower(strings.TrimSpace(repository)); len(repository) != 0 {
+			tags = append(tags, repositoryTag(repository))
+		}
+	}
+
+	if len(tags) == 0 {
+		return []string{allTag}
+	}
+
+	return tags
+}
+
+// evict removes every cached search covering given repositories, including the unfiltered ones
+func (a App) evict(ctx context.Context, repositories []string) error {
+	tags := []string{allTag}
+	for _, repository := range repositories {
+		tags = append(tags, repositoryTag(repository))
+	}
+
+	return a.cache.Evict(ctx, tags)
+}
+
+// invalidator coalesces invalidations of repositories received during a window into a single flush
+type invalidator struct {
+	timer   *time.Timer
+	pending map[string]struct{}
+	onFlush func(context.Context, []string) error
+	window  time.Duration
+	mutex   sync.Mutex
+}
+
+func newInvalidator(window time.Duration, onFlush func(context.Context, []string) error) *invalidator {
+	return &invalidator{
+		window:  window,
+		onFlush: onFlush,
+		pending: make(map[string]struct{}),
+	}
+}
+
+func (i *invalidator) Add(repositories ...string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	for _, repository := range repositories {
+		i.pending[repository] = struct{}{}
+	}
+
+	if i.timer == nil {
+		i.timer = time.AfterFunc(i.window, i.flush)
+	}
+}
+
+func (i *invalidator) Close() {
+	i.mutex.Lock()
+	if i.timer != nil {
+		i.timer.Stop()
+	}
+	i.mutex.Unlock()
+
+	i.flush()
+}
+
+func (i *invalidator) flush() {
+	i.mutex.Lock()
+
+	repositories := make([]string, 0, len(i.pending))
+	for repository := range i.pending {
+		repositories = append(repositories, repository)
+	}
+
+	i.pending = make(map[string]struct{})
+	i.timer = nil
+
+	i.mutex.Unlock()
+
+	if len(repositories) == 0 {
+		return
+	}
+
+	sort.Strings(repositories)
+
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	if err := i.onFlush(ctx, repositories); err != nil {
+		logger.Error("invalidate cache of %s: %s", strings.Join(repositories, ", "), err)
+	}
+}
@@ -0,0 +1,71 @@
+package adapter
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheTags(t *testing.T) {
+	cases := map[string]struct {
+		repositories []string
+		want         []string
+	}{
+		"nil": {
+			nil,
+			[]string{allTag},
+		},
+		"empty values": {
+			[]string{"", "  "},
+			[]string{allTag},
+		},
+		"repositories": {
+			[]string{"ViBiOh/herodote", "vibioh/ketchup"},
+			[]string{repositoryTag("vibioh/herodote"), repositoryTag("vibioh/ketchup")},
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := cacheTags(tc.repositories); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("cacheTags() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInvalidator(t *testing.T) {
+	var mutex sync.Mutex
+	var flushes [][]string
+
+	instance := newInvalidator(time.Millisecond*50, func(_ context.Context, repositories []string) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		flushes = append(flushes, repositories)
+
+		return nil
+	})
+
+	instance.Add("vibioh/ketchup")
+	instance.Add("vibioh/herodote", "vibioh/ketchup")
+
+	time.Sleep(time.Millisecond * 200)
+
+	instance.Add("vibioh/fibr")
+	instance.Close()
+
+	want := [][]string{
+		{"vibioh/herodote", "vibioh/ketchup"},
+		{"vibioh/fibr"},
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if !reflect.DeepEqual(flushes, want) {
+		t.Errorf("invalidator = %+v, want %+v", flushes, want)
+	}
+}
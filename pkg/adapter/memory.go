@@ -0,0 +1,171 @@
+package adapter
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/cache"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type memoryEntry struct {
+	expiration time.Time
+	key        string
+	tags       []string
+	value      model.CommitsList
+}
+
+// memoryCache is a LRU cache bounded in size and in time, used when no Redis is configured
+type memoryCache struct {
+	lastModified time.Time
+	clock        func() time.Time
+	items        map[string]*list.Element
+	tags         map[string]map[string]struct{}
+	lru          *list.List
+	metrics      metrics
+	ttl          time.Duration
+	size         uint
+	generation   uint64
+	mutex        sync.Mutex
+}
+
+func newMemoryCache(size uint, ttl time.Duration, prometheusRegisterer prometheus.Registerer) *memoryCache {
+	return &memoryCache{
+		lastModified: time.Now(),
+		clock:        time.Now,
+		size:         size,
+		ttl:          ttl,
+		items:        make(map[string]*list.Element),
+		tags:         make(map[string]map[string]struct{}),
+		lru:          list.New(),
+		metrics:      newMetrics(prometheusRegisterer, "memory"),
+	}
+}
+
+func (m *memoryCache) Load(ctx context.Context, key string, tags []string, onMiss func(context.Context) (model.CommitsList, error)) (model.CommitsList, error) {
+	if m.size == 0 || cache.IsBypassed(ctx) {
+		m.metrics.miss()
+		return onMiss(ctx)
+	}
+
+	value, ok, generation := m.get(key)
+	if ok {
+		m.metrics.hit()
+		return value, nil
+	}
+
+	m.metrics.miss()
+
+	value, err := onMiss(ctx)
+	if err == nil {
+		m.set(key, tags, value, generation)
+	}
+
+	return value, err
+}
+
+func (m *memoryCache) Evict(_ context.Context, tags []string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.generation++
+	m.lastModified = m.clock()
+
+	evicted := 0
+
+	for _, tag := range tags {
+		for key := range m.tags[tag] {
+			if element, ok := m.items[key]; ok {
+				m.remove(element)
+				evicted++
+			}
+		}
+	}
+
+	m.metrics.evict(evicted)
+
+	return nil
+}
+
+func (m *memoryCache) LastModified(_ context.Context) (time.Time, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.lastModified, nil
+}
+
+func (m *memoryCache) get(key string) (model.CommitsList, bool, uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	element, ok := m.items[key]
+	if !ok {
+		return model.CommitsList{}, false, m.generation
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if m.clock().After(entry.expiration) {
+		m.remove(element)
+		m.metrics.evict(1)
+
+		return model.CommitsList{}, false, m.generation
+	}
+
+	m.lru.MoveToFront(element)
+
+	return entry.value, true, m.generation
+}
+
+func (m *memoryCache) set(key string, tags []string, value model.CommitsList, generation uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// an eviction happened while we were fetching, value may be stale
+	if generation != m.generation {
+		return
+	}
+
+	if element, ok := m.items[key]; ok {
+		m.remove(element)
+	}
+
+	m.items[key] = m.lru.PushFront(&memoryEntry{
+		key:        key,
+		tags:       tags,
+		value:      value,
+		expiration: m.clock().Add(m.ttl),
+	})
+
+	for _, tag := range tags {
+		keys, ok := m.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			m.tags[tag] = keys
+		}
+
+		keys[key] = struct{}{}
+	}
+
+	for uint(m.lru.Len()) > m.size {
+		m.remove(m.lru.Back())
+		m.metrics.evict(1)
+	}
+}
+
+func (m *memoryCache) remove(element *list.Element) {
+	entry := m.lru.Remove(element).(*memoryEntry)
+	delete(m.items, entry.key)
+
+	for _, tag := range entry.tags {
+		if keys, ok := m.tags[tag]; ok {
+			delete(keys, entry.key)
+
+			if len(keys) == 0 {
+				delete(m.tags, tag)
+			}
+		}
+	}
+}
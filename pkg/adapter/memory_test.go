@@ -0,0 +1,103 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+func loadCount(t *testing.T, instance *memoryCache, key string, tags []string, count uint) uint {
+	t.Helper()
+
+	value, err := instance.Load(context.Background(), key, tags, func(_ context.Context) (model.CommitsList, error) {
+		return model.CommitsList{TotalCount: count}, nil
+	})
+	if err != nil {
+		t.Fatalf("Load() = %s", err)
+	}
+
+	return value.TotalCount
+}
+
+func TestMemoryCache(t *testing.T) {
+	now := time.Date(2023, 7, 20, 9, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		run  func(*memoryCache) uint
+		want uint
+	}{
+		"hit": {
+			func(instance *memoryCache) uint {
+				loadCount(t, instance, "first", []string{allTag}, 1)
+				return loadCount(t, instance, "first", []string{allTag}, 2)
+			},
+			1,
+		},
+		"size": {
+			func(instance *memoryCache) uint {
+				loadCount(t, instance, "first", []string{allTag}, 1)
+				loadCount(t, instance, "second", []string{allTag}, 2)
+				loadCount(t, instance, "third", []string{allTag}, 3)
+				return loadCount(t, instance, "first", []string{allTag}, 4)
+			},
+			4,
+		},
+		"recently used": {
+			func(instance *memoryCache) uint {
+				loadCount(t, instance, "first", []string{allTag}, 1)
+				loadCount(t, instance, "second", []string{allTag}, 2)
+				loadCount(t, instance, "first", []string{allTag}, 3)
+				loadCount(t, instance, "third", []string{allTag}, 4)
+				return loadCount(t, instance, "first", []string{allTag}, 5)
+			},
+			1,
+		},
+		"ttl": {
+			func(instance *memoryCache) uint {
+				loadCount(t, instance, "first", []string{allTag}, 1)
+				instance.clock = func() time.Time { return now.Add(time.Hour) }
+				return loadCount(t, instance, "first", []string{allTag}, 2)
+			},
+			2,
+		},
+		"evict tag": {
+			func(instance *memoryCache) uint {
+				loadCount(t, instance, "first", []string{repositoryTag("vibioh/herodote")}, 1)
+				_ = instance.Evict(context.Background(), []string{allTag, repositoryTag("vibioh/herodote")})
+				return loadCount(t, instance, "first", []string{repositoryTag("vibioh/herodote")}, 2)
+			},
+			2,
+		},
+		"evict other tag": {
+			func(instance *memoryCache) uint {
+				loadCount(t, instance, "first", []string{repositoryTag("vibioh/herodote")}, 1)
+				_ = instance.Evict(context.Background(), []string{allTag, repositoryTag("vibioh/ketchup")})
+				return loadCount(t, instance, "first", []string{repositoryTag("vibioh/herodote")}, 2)
+			},
+			1,
+		},
+		"stale fetch": {
+			func(instance *memoryCache) uint {
+				_, _ = instance.Load(context.Background(), "first", []string{allTag}, func(ctx context.Context) (model.CommitsList, error) {
+					_ = instance.Evict(ctx, []string{allTag})
+					return model.CommitsList{TotalCount: 1}, nil
+				})
+				return loadCount(t, instance, "first", []string{allTag}, 2)
+			},
+			2,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			instance := newMemoryCache(2, time.Minute, nil)
+			instance.clock = func() time.Time { return now }
+
+			if got := tc.run(instance); got != tc.want {
+				t.Errorf("memoryCache = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
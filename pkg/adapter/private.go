@@ -0,0 +1,93 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+const privatesTTL = time.Minute
+
+// privates keeps in memory the private repositories, reloaded periodically to catch changes of other instances
+type privates struct {
+	expiration time.Time
+	values     model.PrivateRepositories
+	mutex      sync.RWMutex
+}
+
+func (p *privates) get() (model.PrivateRepositories, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if time.Now().After(p.expiration) {
+		return nil, false
+	}
+
+	return p.values, true
+}
+
+func (p *privates) set(list []model.PrivateRepository) model.PrivateRepositories {
+	values := model.NewPrivateRepositories(list)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.values = values
+	p.expiration = time.Now().Add(privatesTTL)
+
+	return values
+}
+
+func (p *privates) expire() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.expiration = time.Time{}
+}
+
+func (a App) ListPrivateRepositories(ctx context.Context) ([]model.PrivateRepository, error) {
+	return a.store.ListPrivateRepositories(ctx)
+}
+
+// PrivateRepositories returns the private repositories with their tenant, from memory when fresh
+func (a App) PrivateRepositories(ctx context.Context) (model.PrivateRepositories, error) {
+	if values, ok := a.privates.get(); ok {
+		return values, nil
+	}
+
+	list, err := a.store.ListPrivateRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list private repositories: %w", err)
+	}
+
+	return a.privates.set(list), nil
+}
+
+func (a App) SetPrivateRepository(ctx context.Context, private model.PrivateRepository) error {
+	private.Repository = a.resolveRepository(ctx, private.Repository)
+
+	if err := a.store.SetPrivateRepository(ctx, private); err != nil {
+		return fmt.Errorf("set: %w", err)
+	}
+
+	a.privates.expire()
+	a.invalidator.Add(private.Repository)
+
+	return nil
+}
+
+func (a App) DeletePrivateRepository(ctx context.Context, repository string) error {
+	repository = a.resolveRepository(ctx, repository)
+
+	if err := a.store.DeletePrivateRepository(ctx, repository); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	a.privates.expire()
+	a.invalidator.Add(repository)
+
+	return nil
+}
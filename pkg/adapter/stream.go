@@ -0,0 +1,116 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+)
+
+const (
+	streamChannel = "herodote:commits"
+
+	// streamBuffer is the number of events a subscriber can lag behind before being dropped
+	streamBuffer = 64
+)
+
+// stream broadcasts saved commits to local subscribers, through Redis to reach subscribers of every instance
+type stream struct {
+	redis       redis.Client
+	subscribers map[chan model.CommitEvent]struct{}
+	unsubscribe func(context.Context) error
+	done        <-chan struct{}
+	mutex       sync.Mutex
+}
+
+func newStream(redisClient redis.Client) *stream {
+	instance := &stream{
+		redis:       redisClient,
+		subscribers: make(map[chan model.CommitEvent]struct{}),
+	}
+
+	if redisClient.Enabled() {
+		instance.done, instance.unsubscribe = redis.SubscribeFor(context.Background(), redisClient, streamChannel, func(event model.CommitEvent, err error) {
+			if err != nil {
+				logger.Error("unmarshal commit event: %s", err)
+				return
+			}
+
+			instance.broadcast(event)
+		})
+	}
+
+	return instance
+}
+
+// publish sends the event to every instance, or only to local subscribers when Redis is disabled or unreachable
+func (s *stream) publish(ctx context.Context, event model.CommitEvent) {
+	if s.redis.Enabled() {
+		err := s.redis.PublishJSON(ctx, streamChannel, event)
+		if err == nil || errors.Is(err, redis.ErrNoSubscriber) {
+			return
+		}
+
+		logger.Error("publish commit event: %s", err)
+	}
+
+	s.broadcast(event)
+}
+
+func (s *stream) broadcast(event model.CommitEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			// a slow subscriber is closed, it resumes from its last event on reconnection
+			delete(s.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// subscribe returns a channel of saved commits, closed when the subscriber is too slow or when the stream is closed
+func (s *stream) subscribe() (<-chan model.CommitEvent, func()) {
+	subscriber := make(chan model.CommitEvent, streamBuffer)
+
+	s.mutex.Lock()
+	s.subscribers[subscriber] = struct{}{}
+	s.mutex.Unlock()
+
+	return subscriber, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if _, ok := s.subscribers[subscriber]; ok {
+			delete(s.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+func (s *stream) close() {
+	if s.unsubscribe != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		defer cancel()
+
+		if err := s.unsubscribe(ctx); err != nil {
+			logger.Error("unsubscribe from commit events: %s", err)
+		}
+
+		<-s.done
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for subscriber := range s.subscribers {
+		delete(s.subscribers, subscriber)
+		close(subscriber)
+	}
+}
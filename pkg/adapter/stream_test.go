@@ -0,0 +1,94 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+)
+
+func receivedIDs(events <-chan model.CommitEvent) ([]uint64, bool) {
+	var ids []uint64
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return ids, true
+			}
+
+			ids = append(ids, event.ID)
+		default:
+			return ids, false
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	cases := map[string]struct {
+		run        func(*stream, func())
+		wantIDs    []uint64
+		wantClosed bool
+	}{
+		"broadcast": {
+			func(instance *stream, _ func()) {
+				instance.publish(context.Background(), model.CommitEvent{ID: 1})
+				instance.publish(context.Background(), model.CommitEvent{ID: 2})
+			},
+			[]uint64{1, 2},
+			false,
+		},
+		"unsubscribe": {
+			func(instance *stream, unsubscribe func()) {
+				instance.publish(context.Background(), model.CommitEvent{ID: 1})
+				unsubscribe()
+				unsubscribe()
+				instance.publish(context.Background(), model.CommitEvent{ID: 2})
+			},
+			[]uint64{1},
+			true,
+		},
+		"slow subscriber": {
+			func(instance *stream, _ func()) {
+				for i := uint64(1); i <= streamBuffer+1; i++ {
+					instance.publish(context.Background(), model.CommitEvent{ID: i})
+				}
+			},
+			nil,
+			true,
+		},
+		"close": {
+			func(instance *stream, _ func()) {
+				instance.close()
+			},
+			nil,
+			true,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			instance := newStream(redis.Noop{})
+			events, unsubscribe := instance.subscribe()
+
+			tc.run(instance, unsubscribe)
+
+			gotIDs, gotClosed := receivedIDs(events)
+
+			if gotClosed != tc.wantClosed {
+				t.Errorf("stream closed=%t, want %t", gotClosed, tc.wantClosed)
+			}
+
+			if tc.wantIDs != nil && len(gotIDs) != len(tc.wantIDs) {
+				t.Fatalf("stream = %v, want %v", gotIDs, tc.wantIDs)
+			}
+
+			for index, id := range tc.wantIDs {
+				if gotIDs[index] != id {
+					t.Errorf("stream = %v, want %v", gotIDs, tc.wantIDs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+func (a App) ListSubscriptions(ctx context.Context, owner string) ([]model.Subscription, error) {
+	return a.store.ListSubscriptions(ctx, owner)
+}
+
+func (a App) CreateSubscription(ctx context.Context, subscription model.Subscription) (uint64, error) {
+	return a.store.CreateSubscription(ctx, subscription)
+}
+
+func (a App) DeleteSubscription(ctx context.Context, id uint64, owner string) error {
+	return a.store.DeleteSubscription(ctx, id, owner)
+}
@@ -0,0 +1,27 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+func (a App) ListTokens(ctx context.Context) ([]model.Token, error) {
+	return a.store.ListTokens(ctx)
+}
+
+func (a App) GetTokenByHash(ctx context.Context, hash string) (model.Token, string, error) {
+	return a.store.GetTokenByHash(ctx, hash)
+}
+
+func (a App) CreateToken(ctx context.Context, token model.Token, hash string) error {
+	return a.store.CreateToken(ctx, token, hash)
+}
+
+func (a App) DeleteToken(ctx context.Context, name string) error {
+	return a.store.DeleteToken(ctx, name)
+}
+
+func (a App) TouchToken(ctx context.Context, name string) error {
+	return a.store.TouchToken(ctx, name)
+}
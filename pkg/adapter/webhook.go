@@ -0,0 +1,48 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+// ListWebhooks returns webhooks without their secret
+func (a App) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	list, err := a.store.ListWebhooks(ctx)
+
+	for index := range list {
+		list[index].Secret = ""
+	}
+
+	return list, err
+}
+
+func (a App) CreateWebhook(ctx context.Context, webhook model.Webhook) error {
+	if err := a.store.CreateWebhook(ctx, webhook); err != nil {
+		return err
+	}
+
+	a.expireWebhooks()
+
+	return nil
+}
+
+func (a App) DeleteWebhook(ctx context.Context, name string) error {
+	if err := a.store.DeleteWebhook(ctx, name); err != nil {
+		return err
+	}
+
+	a.expireWebhooks()
+
+	return nil
+}
+
+func (a App) ListWebhookFailures(ctx context.Context, name string, pageSize uint) ([]model.WebhookFailure, error) {
+	return a.store.ListWebhookFailures(ctx, name, pageSize)
+}
+
+func (a App) expireWebhooks() {
+	if a.notifier != nil {
+		a.notifier.Expire()
+	}
+}
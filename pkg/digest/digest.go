@@ -0,0 +1,188 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+	"github.com/ViBiOh/httputils/v4/pkg/request"
+)
+
+const (
+	formatSlack      = "slack"
+	formatMattermost = "mattermost"
+
+	isoDateLayout = "2006-01-02"
+)
+
+// Store summarizes commits of a period
+type Store interface {
+	Digest(ctx context.Context, since, until time.Time, highlights uint) (model.Digest, error)
+}
+
+type App struct {
+	store      Store
+	clock      func() time.Time
+	publicURL  string
+	title      string
+	format     string
+	urls       []string
+	period     time.Duration
+	highlights uint
+}
+
+type Config struct {
+	urls       *string
+	format     *string
+	period     *time.Duration
+	publicURL  *string
+	title      *string
+	highlights *uint
+}
+
+func Flags(fs *flag.FlagSet, prefix string) Config {
+	return Config{
+		urls:       flags.New("URL", "Incoming webhook URLs, comma separated").Prefix(prefix).DocPrefix("digest").String(fs, "", nil),
+		format:     flags.New("Format", "Message format: slack or mattermost").Prefix(prefix).DocPrefix("digest").String(fs, formatSlack, nil),
+		period:     flags.New("Period", "Period covered by the digest, e.g. 24h for daily, 168h for weekly").Prefix(prefix).DocPrefix("digest").Duration(fs, time.Hour*24, nil),
+		publicURL:  flags.New("PublicURL", "Herodote public URL, for links").Prefix(prefix).DocPrefix("digest").String(fs, "https://herodote.vibioh.fr", nil),
+		title:      flags.New("Title", "Title of the digest").Prefix(prefix).DocPrefix("digest").String(fs, "Herodote digest", nil),
+		highlights: flags.New("Highlights", "Breaking changes and reverts highlighted").Prefix(prefix).DocPrefix("digest").Uint(fs, 10, nil),
+	}
+}
+
+func New(config Config, store Store) (App, error) {
+	app := App{
+		store:      store,
+		clock:      time.Now,
+		format:     strings.ToLower(strings.TrimSpace(*config.format)),
+		period:     *config.period,
+		publicURL:  strings.TrimSuffix(strings.TrimSpace(*config.publicURL), "/"),
+		title:      strings.TrimSpace(*config.title),
+		highlights: *config.highlights,
+	}
+
+	for _, rawURL := range strings.Split(*config.urls, ",") {
+		if rawURL = strings.TrimSpace(rawURL); len(rawURL) != 0 {
+			app.urls = append(app.urls, rawURL)
+		}
+	}
+
+	if len(app.urls) == 0 {
+		return App{}, errors.New("digest url is required")
+	}
+
+	if app.format != formatSlack && app.format != formatMattermost {
+		return App{}, fmt.Errorf("digest format `%s` is unknown", app.format)
+	}
+
+	if app.period <= 0 {
+		return App{}, errors.New("digest period must be positive")
+	}
+
+	return app, nil
+}
+
+// Send posts the digest of the period ending now to every URL, nothing is sent without commits
+func (a App) Send(ctx context.Context) error {
+	until := a.clock()
+
+	digest, err := a.store.Digest(ctx, until.Add(-a.period), until, a.highlights)
+	if err != nil {
+		return fmt.Errorf("digest: %w", err)
+	}
+
+	if digest.Count() == 0 {
+		logger.Info("No commit since %s, digest is not sent", digest.Since.Format(time.RFC3339))
+		return nil
+	}
+
+	var payload any
+	if a.format == formatMattermost {
+		payload = a.mattermostMessage(digest)
+	} else {
+		payload = a.slackMessage(digest)
+	}
+
+	var errs []error
+
+	for _, hookURL := range a.urls {
+		if err = send(ctx, hookURL, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func send(ctx context.Context, hookURL string, payload any) error {
+	resp, err := request.Post(hookURL).JSON(ctx, payload)
+	if err != nil {
+		return fmt.Errorf("post digest: %w", err)
+	}
+
+	if err = request.DiscardBody(resp.Body); err != nil {
+		return fmt.Errorf("discard body: %w", err)
+	}
+
+	return nil
+}
+
+// viewURL returns the Herodote view of commits of the digest, filtered by given params
+func (a App) viewURL(digest model.Digest, params url.Values) string {
+	if params == nil {
+		params = url.Values{}
+	}
+
+	// `after` is exclusive
+	params.Set("after", digest.Since.AddDate(0, 0, -1).Format(isoDateLayout))
+
+	return fmt.Sprintf("%s/?%s", a.publicURL, params.Encode())
+}
+
+func commitURL(commit model.Commit) string {
+	return fmt.Sprintf("https://%s/%s/commit/%s", commit.Remote, commit.Repository, commit.Hash)
+}
+
+func highlightLabel(commit model.Commit) string {
+	var labels []string
+
+	if commit.Breaking {
+		labels = append(labels, "BREAKING CHANGE")
+	}
+
+	if commit.Revert {
+		labels = append(labels, "Revert")
+	}
+
+	return strings.Join(labels, ", ")
+}
+
+func commitTitle(commit model.Commit) string {
+	if len(commit.Component) != 0 {
+		return fmt.Sprintf("%s(%s): %s", commit.Type, commit.Component, commit.Content)
+	}
+
+	return fmt.Sprintf("%s: %s", commit.Type, commit.Content)
+}
+
+func typesSummary(repository model.RepositoryDigest) string {
+	types := make([]string, 0, len(repository.Types))
+
+	for _, name := range sortedKeys(repository.Types) {
+		types = append(types, fmt.Sprintf("%d %s", repository.Types[name], name))
+	}
+
+	return strings.Join(types, ", ")
+}
+
+func (a App) summary(digest model.Digest) string {
+	return fmt.Sprintf("%s: %d commits in %d repositories since %s", a.title, digest.Count(), len(digest.Repositories), digest.Since.Format(isoDateLayout))
+}
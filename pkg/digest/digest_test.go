@@ -0,0 +1,123 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+type stubStore struct {
+	digest model.Digest
+}
+
+func (s stubStore) Digest(_ context.Context, since, until time.Time, _ uint) (model.Digest, error) {
+	s.digest.Since = since
+	s.digest.Until = until
+
+	return s.digest, nil
+}
+
+func TestSend(t *testing.T) {
+	now := time.Date(2023, 7, 20, 6, 0, 0, 0, time.UTC)
+
+	digest := model.Digest{
+		Repositories: []model.RepositoryDigest{
+			{Repository: "vibioh/herodote", Count: 4, Types: map[string]uint64{"fix": 1, "feat": 3}},
+			{Repository: "vibioh/ketchup", Count: 1, Types: map[string]uint64{"chore": 1}},
+		},
+		Highlights: []model.Commit{
+			{Repository: "vibioh/herodote", Remote: "github.com", Hash: "1a2bc34d", Type: "feat", Component: "api", Content: "Remove <v1> endpoints", Breaking: true},
+		},
+	}
+
+	cases := map[string]struct {
+		format   string
+		digest   model.Digest
+		want     []string
+		wantSent bool
+	}{
+		"slack": {
+			formatSlack,
+			digest,
+			[]string{
+				`"text":"Herodote digest: 5 commits in 2 repositories since 2023-07-19"`,
+				`{"text":{"text":"Herodote digest","type":"plain_text"},"type":"header"}`,
+				`• <https://herodote.vibioh.fr/?after=2023-07-18&repository=vibioh%2Fherodote|vibioh/herodote>: *4* (3 feat, 1 fix)`,
+				`{"type":"divider"}`,
+				`• *BREAKING CHANGE* <https://github.com/vibioh/herodote/commit/1a2bc34d|vibioh/herodote> feat(api): Remove &lt;v1&gt; endpoints`,
+			},
+			true,
+		},
+		"mattermost": {
+			formatMattermost,
+			digest,
+			[]string{
+				`#### Herodote digest`,
+				`| [vibioh/ketchup](https://herodote.vibioh.fr/?after=2023-07-18&repository=vibioh%2Fketchup) | 1 | 1 chore |`,
+				`- **BREAKING CHANGE** [vibioh/herodote](https://github.com/vibioh/herodote/commit/1a2bc34d) feat(api): Remove <v1> endpoints`,
+			},
+			true,
+		},
+		"empty": {
+			formatSlack,
+			model.Digest{},
+			nil,
+			false,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			var payload string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var content any
+				if err := json.NewDecoder(r.Body).Decode(&content); err != nil {
+					t.Errorf("invalid JSON payload: %s", err)
+				}
+
+				// re-encode without HTML escaping for readable expectations
+				var builder strings.Builder
+				encoder := json.NewEncoder(&builder)
+				encoder.SetEscapeHTML(false)
+				_ = encoder.Encode(content)
+
+				payload = builder.String()
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			instance := App{
+				store:      stubStore{digest: tc.digest},
+				clock:      func() time.Time { return now },
+				urls:       []string{server.URL},
+				format:     tc.format,
+				period:     time.Hour * 24,
+				publicURL:  "https://herodote.vibioh.fr",
+				title:      "Herodote digest",
+				highlights: 10,
+			}
+
+			if err := instance.Send(context.Background()); err != nil {
+				t.Errorf("Send() = `%s`", err)
+			}
+
+			if sent := len(payload) != 0; sent != tc.wantSent {
+				t.Errorf("Send() sent = %t, want %t", sent, tc.wantSent)
+			}
+
+			for _, want := range tc.want {
+				if !strings.Contains(payload, want) {
+					t.Errorf("Send() = `%s`, want `%s`", payload, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package digest
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+// slackTextLimit is the maximum length of a section's text in Slack
+const slackTextLimit = 3000
+
+var (
+	slackEscaper    = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	markdownEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`")
+)
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Text *slackText `json:"text,omitempty"`
+	Type string     `json:"type"`
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type mattermostMessage struct {
+	Text string `json:"text"`
+}
+
+func (a App) slackMessage(digest model.Digest) slackMessage {
+	message := slackMessage{
+		Text: a.summary(digest),
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{Type: "plain_text", Text: a.title}},
+			slackSection(fmt.Sprintf("*%d commits* in %d repositories since <%s|%s>", digest.Count(), len(digest.Repositories), a.viewURL(digest, nil), digest.Since.Format(isoDateLayout))),
+		},
+	}
+
+	lines := make([]string, 0, len(digest.Repositories))
+	for _, repository := range digest.Repositories {
+		lines = append(lines, fmt.Sprintf("• <%s|%s>: *%d* (%s)", a.viewURL(digest, url.Values{"repository": {repository.Repository}}), slackEscaper.Replace(repository.Repository), repository.Count, slackEscaper.Replace(typesSummary(repository))))
+	}
+
+	message.Blocks = append(message.Blocks, slackSections(lines)...)
+
+	if len(digest.Highlights) == 0 {
+		return message
+	}
+
+	lines = []string{":warning: *Breaking changes and reverts*"}
+	for _, commit := range digest.Highlights {
+		lines = append(lines, fmt.Sprintf("• *%s* <%s|%s> %s", highlightLabel(commit), commitURL(commit), slackEscaper.Replace(commit.Repository), slackEscaper.Replace(commitTitle(commit))))
+	}
+
+	message.Blocks = append(message.Blocks, slackBlock{Type: "divider"})
+	message.Blocks = append(message.Blocks, slackSections(lines)...)
+
+	return message
+}
+
+func slackSection(text string) slackBlock {
+	return slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}}
+}
+
+// slackSections groups lines in sections under the length limit of Slack, a line is never split
+func slackSections(lines []string) []slackBlock {
+	var blocks []slackBlock
+	var current strings.Builder
+
+	for _, line := range lines {
+		if current.Len() != 0 && current.Len()+len(line)+1 > slackTextLimit {
+			blocks = append(blocks, slackSection(current.String()))
+			current.Reset()
+		}
+
+		if current.Len() != 0 {
+			current.WriteString("\n")
+		}
+
+		current.WriteString(line)
+	}
+
+	if current.Len() != 0 {
+		blocks = append(blocks, slackSection(current.String()))
+	}
+
+	return blocks
+}
+
+func (a App) mattermostMessage(digest model.Digest) mattermostMessage {
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "#### %s\n\n", markdownEscaper.Replace(a.title))
+	fmt.Fprintf(&text, "**%d commits** in %d repositories since [%s](%s)\n\n", digest.Count(), len(digest.Repositories), digest.Since.Format(isoDateLayout), a.viewURL(digest, nil))
+
+	text.WriteString("| Repository | Commits | Types |\n|:---|---:|:---|\n")
+	for _, repository := range digest.Repositories {
+		fmt.Fprintf(&text, "| [%s](%s) | %d | %s |\n", markdownEscaper.Replace(repository.Repository), a.viewURL(digest, url.Values{"repository": {repository.Repository}}), repository.Count, typesSummary(repository))
+	}
+
+	if len(digest.Highlights) != 0 {
+		text.WriteString("\n#### :warning: Breaking changes and reverts\n\n")
+
+		for _, commit := range digest.Highlights {
+			fmt.Fprintf(&text, "- **%s** [%s](%s) %s\n", highlightLabel(commit), markdownEscaper.Replace(commit.Repository), commitURL(commit), markdownEscaper.Replace(commitTitle(commit)))
+		}
+	}
+
+	return mattermostMessage{
+		Text: text.String(),
+	}
+}
+
+func sortedKeys(values map[string]uint64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
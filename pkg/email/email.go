@@ -0,0 +1,246 @@
+package email
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	htmlTemplate "html/template"
+	"net"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	textTemplate "text/template"
+	"time"
+
+	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/herodote/pkg/herodote"
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+)
+
+const (
+	isoDateLayout = "2006-01-02"
+	templateName  = "changelog"
+
+	// dueTolerance allows a scheduler running at a fixed time to send subscriptions sent a bit later the previous time
+	dueTolerance = time.Hour
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Store lists subscriptions and commits of a period
+type Store interface {
+	ListSubscriptions(ctx context.Context, owner string) ([]model.Subscription, error)
+	ListCommits(ctx context.Context, since, until time.Time) ([]model.Commit, error)
+	ListPrivateRepositories(context.Context) ([]model.PrivateRepository, error)
+	MarkSubscriptionSent(ctx context.Context, id uint64, sent time.Time) error
+}
+
+type sendFunc func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+
+type App struct {
+	store      Store
+	auth       smtp.Auth
+	clock      func() time.Time
+	send       sendFunc
+	html       *htmlTemplate.Template
+	text       *textTemplate.Template
+	vocabulary vocabulary.App
+	address    string
+	from       string
+	publicURL  string
+	title      string
+}
+
+type Config struct {
+	host      *string
+	port      *uint
+	username  *string
+	password  *string
+	from      *string
+	publicURL *string
+	title     *string
+}
+
+func Flags(fs *flag.FlagSet, prefix string) Config {
+	return Config{
+		host:      flags.New("Host", "SMTP server host").Prefix(prefix).DocPrefix("email").String(fs, "localhost", nil),
+		port:      flags.New("Port", "SMTP server port").Prefix(prefix).DocPrefix("email").Uint(fs, 587, nil),
+		username:  flags.New("Username", "SMTP username, authentication is disabled if empty").Prefix(prefix).DocPrefix("email").String(fs, "", nil),
+		password:  flags.New("Password", "SMTP password").Prefix(prefix).DocPrefix("email").String(fs, "", nil),
+		from:      flags.New("From", "Sender address").Prefix(prefix).DocPrefix("email").String(fs, "herodote@localhost", nil),
+		publicURL: flags.New("PublicURL", "Herodote public URL, for links").Prefix(prefix).DocPrefix("email").String(fs, "https://herodote.vibioh.fr", nil),
+		title:     flags.New("Title", "Title of emails").Prefix(prefix).DocPrefix("email").String(fs, "Herodote changelog", nil),
+	}
+}
+
+func New(config Config, store Store, vocabularyApp vocabulary.App) (App, error) {
+	funcs := htmlTemplate.FuncMap{
+		"commitURL": commitURL,
+	}
+
+	for name, fn := range herodote.FuncMap {
+		funcs[name] = fn
+	}
+
+	html, err := htmlTemplate.New(templateName).Funcs(funcs).ParseFS(templates, "templates/*.html")
+	if err != nil {
+		return App{}, fmt.Errorf("parse html template: %w", err)
+	}
+
+	text, err := textTemplate.New(templateName).Funcs(textTemplate.FuncMap(funcs)).ParseFS(templates, "templates/*.txt")
+	if err != nil {
+		return App{}, fmt.Errorf("parse text template: %w", err)
+	}
+
+	host := strings.TrimSpace(*config.host)
+
+	app := App{
+		store:      store,
+		vocabulary: vocabularyApp,
+		clock:      time.Now,
+		send:       smtp.SendMail,
+		html:       html,
+		text:       text,
+		address:    net.JoinHostPort(host, strconv.FormatUint(uint64(*config.port), 10)),
+		from:       strings.TrimSpace(*config.from),
+		publicURL:  strings.TrimSuffix(strings.TrimSpace(*config.publicURL), "/"),
+		title:      strings.TrimSpace(*config.title),
+	}
+
+	if username := strings.TrimSpace(*config.username); len(username) != 0 {
+		app.auth = smtp.PlainAuth("", username, *config.password, host)
+	}
+
+	return app, nil
+}
+
+// Send emails the changelog to every due subscription
+func (a App) Send(ctx context.Context) error {
+	now := a.clock()
+
+	subscriptions, err := a.store.ListSubscriptions(ctx, "")
+	if err != nil {
+		return fmt.Errorf("list subscriptions: %w", err)
+	}
+
+	var due []model.Subscription
+	since := now
+
+	for _, subscription := range subscriptions {
+		if subscription.LastSent != nil && now.Sub(*subscription.LastSent) < subscription.Period()-dueTolerance {
+			continue
+		}
+
+		due = append(due, subscription)
+
+		if start := subscriptionStart(subscription, now); start.Before(since) {
+			since = start
+		}
+	}
+
+	if len(due) == 0 {
+		logger.Info("No subscription is due")
+		return nil
+	}
+
+	commits, err := a.store.ListCommits(ctx, since, now)
+	if err != nil {
+		return fmt.Errorf("list commits: %w", err)
+	}
+
+	commits = a.vocabulary.LinkReferences(commits)
+
+	privateList, err := a.store.ListPrivateRepositories(ctx)
+	if err != nil {
+		return fmt.Errorf("list private repositories: %w", err)
+	}
+
+	privates := model.NewPrivateRepositories(privateList)
+
+	var errs []error
+
+	for _, subscription := range due {
+		if err = a.sendSubscription(ctx, subscription, commits, privates, now); err != nil {
+			errs = append(errs, fmt.Errorf("subscription `%d`: %w", subscription.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func subscriptionStart(subscription model.Subscription, now time.Time) time.Time {
+	if subscription.LastSent != nil {
+		return *subscription.LastSent
+	}
+
+	return now.Add(-subscription.Period())
+}
+
+// sendSubscription sends commits matching the subscription, private repositories are restricted to the tenant of its owner
+func (a App) sendSubscription(ctx context.Context, subscription model.Subscription, commits []model.Commit, privates model.PrivateRepositories, now time.Time) error {
+	since := subscriptionStart(subscription, now)
+	visibility := []string{subscription.Tenant}
+
+	var matching []model.Commit
+	for _, commit := range commits {
+		if !commit.Date.Before(since) && subscription.Filter.Matches(commit) && privates.Visible(visibility, commit.Repository) {
+			matching = append(matching, commit)
+		}
+	}
+
+	if len(matching) != 0 {
+		content, err := a.message(subscription, matching, since, now)
+		if err != nil {
+			return fmt.Errorf("message: %w", err)
+		}
+
+		if err = a.send(a.address, a.auth, a.from, []string{subscription.Email}, content); err != nil {
+			return fmt.Errorf("send: %w", err)
+		}
+	}
+
+	// the period is covered even without commit
+	return a.store.MarkSubscriptionSent(ctx, subscription.ID, now)
+}
+
+// viewURL returns the Herodote view of the changelog, repositories patterns can't be expressed as a filter
+func (a App) viewURL(filter model.CommitFilter, since time.Time) string {
+	params := url.Values{}
+
+	for _, repository := range filter.Repositories {
+		if !strings.ContainsAny(repository, `*?[\`) {
+			params.Add("repository", repository)
+		}
+	}
+
+	for _, item := range filter.Types {
+		params.Add("type", item)
+	}
+
+	for _, item := range filter.Components {
+		params.Add("component", item)
+	}
+
+	for _, item := range filter.Paths {
+		params.Add("path", item)
+	}
+
+	for _, item := range filter.Refs {
+		params.Add("ref", item)
+	}
+
+	// `after` is exclusive
+	params.Set("after", since.AddDate(0, 0, -1).Format(isoDateLayout))
+
+	return fmt.Sprintf("%s/?%s", a.publicURL, params.Encode())
+}
+
+func commitURL(commit model.Commit) string {
+	return fmt.Sprintf("https://%s/%s/commit/%s", commit.Remote, commit.Repository, commit.Hash)
+}
@@ -0,0 +1,244 @@
+package email
+
+import (
+	"context"
+	"flag"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
+)
+
+type stubStore struct {
+	sent          map[uint64]time.Time
+	subscriptions []model.Subscription
+	commits       []model.Commit
+}
+
+func (s stubStore) ListSubscriptions(_ context.Context, _ string) ([]model.Subscription, error) {
+	return s.subscriptions, nil
+}
+
+func (s stubStore) ListCommits(_ context.Context, since, until time.Time) ([]model.Commit, error) {
+	var output []model.Commit
+
+	for _, commit := range s.commits {
+		if !commit.Date.Before(since) && commit.Date.Before(until) {
+			output = append(output, commit)
+		}
+	}
+
+	return output, nil
+}
+
+func (s stubStore) ListPrivateRepositories(_ context.Context) ([]model.PrivateRepository, error) {
+	return []model.PrivateRepository{{Repository: "acme/billing", Tenant: "acme"}}, nil
+}
+
+func (s stubStore) MarkSubscriptionSent(_ context.Context, id uint64, sent time.Time) error {
+	s.sent[id] = sent
+
+	return nil
+}
+
+func TestSend(t *testing.T) {
+	now := time.Date(2023, 7, 20, 6, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	lastWeek := now.AddDate(0, 0, -7)
+
+	commits := []model.Commit{
+		{Repository: "vibioh/herodote", Remote: "github.com", Hash: "1a2bc34d", Type: "feat", Component: "api", Content: "Remove <v1> endpoints", Breaking: true, Date: now.Add(-time.Hour)},
+		{Repository: "vibioh/herodote", Remote: "github.com", Hash: "5e6fa78b", Type: "fix", Content: "Handle empty payload, fixes #12", References: model.NewReferences([]string{"#12"}), Date: now.Add(-time.Hour * 30)},
+		{Repository: "vibioh/ketchup", Remote: "github.com", Hash: "9c0de12f", Type: "chore", Content: "Bump dependencies", Date: now.Add(-time.Hour * 2)},
+		{Repository: "acme/billing", Remote: "github.com", Hash: "3b4cd56e", Type: "fix", Content: "Round invoices", Tenant: "acme", Date: now.Add(-time.Hour * 3)},
+	}
+
+	cases := map[string]struct {
+		subscription model.Subscription
+		want         []string
+		wantNot      []string
+		wantSent     bool
+		wantMarked   bool
+	}{
+		"daily": {
+			model.Subscription{ID: 1, Owner: "ci", Email: "dev@example.com", Frequency: model.DailyFrequency, Filter: model.CommitFilter{Repositories: []string{"vibioh/*"}}},
+			[]string{
+				"To: dev@example.com",
+				"Subject: Herodote changelog: 2 commits since 2023-07-19",
+				"Content-Type: multipart/alternative",
+				"Content-Type: text/plain; charset=UTF-8",
+				"Content-Type: text/html; charset=UTF-8",
+				"## Features",
+				"- vibioh/herodote BREAKING CHANGE feat(api): Remove <v1> endpoints",
+				"https://github.com/vibioh/herodote/commit/1a2bc34d",
+				"## Chores",
+				"Remove &lt;v1&gt; endpoints",
+				"https://herodote.vibioh.fr/?after=2023-07-18",
+			},
+			[]string{
+				"Bug fixes",
+			},
+			true,
+			true,
+		},
+		"weekly with filter": {
+			model.Subscription{ID: 2, Owner: "ci", Email: "dev@example.com", Frequency: model.WeeklyFrequency, LastSent: &lastWeek, Filter: model.CommitFilter{Types: []string{"fix"}}},
+			[]string{
+				"Subject: Herodote changelog: 1 commits since 2023-07-13",
+				"## Bug fixes",
+				"- vibioh/herodote fix: Handle empty payload, fixes #12 #12",
+				`<a href="https://github.com/vibioh/herodote/issues/12">#12</a>`,
+				"https://herodote.vibioh.fr/?after=2023-07-12&type=fix",
+			},
+			[]string{
+				"Features",
+			},
+			true,
+			true,
+		},
+		"private of tenant": {
+			model.Subscription{ID: 5, Owner: "ci-acme", Tenant: "acme", Email: "dev@example.com", Frequency: model.WeeklyFrequency, LastSent: &lastWeek, Filter: model.CommitFilter{Types: []string{"fix"}}},
+			[]string{
+				"Subject: Herodote changelog: 2 commits since 2023-07-13",
+				"- acme/billing fix: Round invoices",
+			},
+			nil,
+			true,
+			true,
+		},
+		"not due": {
+			model.Subscription{ID: 3, Owner: "ci", Email: "dev@example.com", Frequency: model.WeeklyFrequency, LastSent: &yesterday},
+			nil,
+			nil,
+			false,
+			false,
+		},
+		"nothing to send": {
+			model.Subscription{ID: 4, Owner: "ci", Email: "dev@example.com", Frequency: model.DailyFrequency, Filter: model.CommitFilter{Types: []string{"perf"}}},
+			nil,
+			nil,
+			false,
+			true,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			fs := flag.NewFlagSet("email", flag.ContinueOnError)
+			emailConfig := Flags(fs, "email")
+			vocabularyConfig := vocabulary.Flags(fs, "")
+
+			if err := fs.Parse(nil); err != nil {
+				t.Fatal(err)
+			}
+
+			vocabularyApp, err := vocabulary.New(vocabularyConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			store := stubStore{
+				subscriptions: []model.Subscription{tc.subscription.Sanitize()},
+				commits:       commits,
+				sent:          make(map[uint64]time.Time),
+			}
+
+			instance, err := New(emailConfig, store, vocabularyApp)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var messages []string
+
+			instance.clock = func() time.Time { return now }
+			instance.send = func(_ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+				messages = append(messages, string(msg))
+
+				return nil
+			}
+
+			if err = instance.Send(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.wantSent != (len(messages) == 1) {
+				t.Fatalf("Send() emailed %d messages, want sent=%t", len(messages), tc.wantSent)
+			}
+
+			if _, marked := store.sent[tc.subscription.ID]; marked != tc.wantMarked {
+				t.Errorf("Send() marked=%t, want %t", marked, tc.wantMarked)
+			}
+
+			if !tc.wantSent {
+				return
+			}
+
+			message := decodeMessage(t, messages[0])
+
+			for _, want := range tc.want {
+				if !strings.Contains(message, want) {
+					t.Errorf("Send() = `%s`, want `%s`", message, want)
+				}
+			}
+
+			for _, wantNot := range tc.wantNot {
+				if strings.Contains(message, wantNot) {
+					t.Errorf("Send() = `%s`, want no `%s`", message, wantNot)
+				}
+			}
+		})
+	}
+}
+
+// decodeMessage parses the multipart message and returns its decoded headers and parts for readable assertions
+func decodeMessage(t *testing.T, message string) string {
+	t.Helper()
+
+	parsed, err := mail.ReadMessage(strings.NewReader(message))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := []string{
+		"To: " + parsed.Header.Get("To"),
+		"Subject: " + subject,
+		"Content-Type: " + mediaType,
+	}
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output = append(output, "Content-Type: "+part.Header.Get("Content-Type"), string(content))
+	}
+
+	return strings.Join(output, "\n")
+}
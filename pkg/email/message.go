@@ -0,0 +1,98 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+type changelog struct {
+	Since        time.Time
+	Title        string
+	URL          string
+	Sections     []model.ChangelogSection
+	Subscription model.Subscription
+	Count        int
+}
+
+// message renders the changelog as a multipart email with a plain text and an html alternative
+func (a App) message(subscription model.Subscription, commits []model.Commit, since, now time.Time) ([]byte, error) {
+	content := changelog{
+		Title:        a.title,
+		Count:        len(commits),
+		Since:        since,
+		URL:          a.viewURL(subscription.Filter, since),
+		Sections:     a.vocabulary.Changelog(commits),
+		Subscription: subscription,
+	}
+
+	var text, html bytes.Buffer
+
+	if err := a.text.ExecuteTemplate(&text, templateName, content); err != nil {
+		return nil, fmt.Errorf("text: %w", err)
+	}
+
+	if err := a.html.ExecuteTemplate(&html, templateName, content); err != nil {
+		return nil, fmt.Errorf("html: %w", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		// the last alternative is the preferred one
+		{"text/plain; charset=UTF-8", text.Bytes()},
+		{"text/html; charset=UTF-8", html.Bytes()},
+	} {
+		if err := writePart(writer, part.contentType, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart: %w", err)
+	}
+
+	var output bytes.Buffer
+
+	fmt.Fprintf(&output, "From: %s\r\n", a.from)
+	fmt.Fprintf(&output, "To: %s\r\n", subscription.Email)
+	fmt.Fprintf(&output, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", fmt.Sprintf("%s: %d commits since %s", a.title, len(commits), since.Format(isoDateLayout))))
+	fmt.Fprintf(&output, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	output.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&output, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", writer.Boundary())
+	output.Write(body.Bytes())
+
+	return output.Bytes(), nil
+}
+
+func writePart(writer *multipart.Writer, contentType string, content []byte) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return fmt.Errorf("create %s part: %w", contentType, err)
+	}
+
+	encoder := quotedprintable.NewWriter(part)
+
+	if _, err = encoder.Write(content); err != nil {
+		return fmt.Errorf("write %s part: %w", contentType, err)
+	}
+
+	if err = encoder.Close(); err != nil {
+		return fmt.Errorf("close %s part: %w", contentType, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	recordSeparator = "\x1e"
+	fieldSeparator  = "\x1f"
+	bodySeparator   = "\x1d"
+
+	// logFormat is the abbreviated hash, the author date, the ref reaching the commit, the subject and the body of a commit, changed paths follow
+	logFormat = "--format=" + recordSeparator + "%h" + fieldSeparator + "%aI" + fieldSeparator + "%S" + fieldSeparator + "%s" + fieldSeparator + "%b" + bodySeparator
+
+	// maxLineSize is the maximum size of a subject or a path
+	maxLineSize = 1 << 20
+)
+
+var (
+	sshRemoteRegex  = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+?)(?:\.git)?/?$`)
+	httpRemoteRegex = regexp.MustCompile(`^[a-z+]+://(?:[^@/]+@)?([^:/]+)(?::\d+)?/(.+?)(?:\.git)?/?$`)
+)
+
+// Commit is a commit read from the log of a repository
+type Commit struct {
+	Date    time.Time
+	Hash    string
+	Source  string
+	Subject string
+	Body    string
+	Paths   []string
+}
+
+// Log calls the handler with every commit reachable from the given refs, HEAD if empty, most recent first.
+// The source of a commit is the full name of a given ref reaching it, e.g. `refs/heads/main`.
+func Log(ctx context.Context, path string, refs []string, handler func(Commit) error) error {
+	if len(refs) == 0 {
+		refs = []string{"HEAD"}
+	}
+
+	refs, err := fullNames(ctx, path, refs)
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"-C", path, "-c", "core.quotePath=false", "log", "--no-color", "--name-only", "--source", logFormat}, refs...)
+	args = append(args, "--")
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+
+	var stdout io.ReadCloser
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err = cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("pipe: %w", err)
+	}
+
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("start git: %w", err)
+	}
+
+	err = parseLog(stdout, handler)
+	if err != nil {
+		// the handler stopped reading, git is stopped rather than blocked on a full pipe
+		_ = cmd.Process.Kill()
+	}
+
+	if waitErr := cmd.Wait(); err == nil && waitErr != nil {
+		return fmt.Errorf("git log: %w: %s", waitErr, strings.TrimSpace(stderr.String()))
+	}
+
+	return err
+}
+
+// fullNames returns the full names of refs, e.g. `refs/heads/main` for `main` or `HEAD`, hashes are kept as is
+func fullNames(ctx context.Context, path string, refs []string) ([]string, error) {
+	output := make([]string, len(refs))
+
+	for index, ref := range refs {
+		name, err := exec.CommandContext(ctx, "git", "-C", path, "rev-parse", "--symbolic-full-name", ref).Output()
+		if err != nil {
+			return nil, fmt.Errorf("resolve ref `%s`: %w", ref, err)
+		}
+
+		if output[index] = strings.TrimSpace(string(name)); len(output[index]) == 0 {
+			output[index] = ref
+		}
+	}
+
+	return output, nil
+}
+
+func parseLog(reader io.Reader, handler func(Commit) error) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var current *Commit
+	var header strings.Builder
+	var inHeader bool
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, recordSeparator) {
+			if current != nil {
+				if err := handler(*current); err != nil {
+					return err
+				}
+
+				current = nil
+			}
+
+			header.Reset()
+			line = strings.TrimPrefix(line, recordSeparator)
+			inHeader = true
+		}
+
+		if !inHeader {
+			if current != nil && len(line) != 0 {
+				current.Paths = append(current.Paths, line)
+			}
+
+			continue
+		}
+
+		// the body spans several lines, until its separator
+		content, ended := strings.CutSuffix(line, bodySeparator)
+		if header.Len() != 0 {
+			header.WriteString("\n")
+		}
+
+		header.WriteString(content)
+
+		if !ended {
+			continue
+		}
+
+		commit, err := parseHeader(header.String())
+		if err != nil {
+			return err
+		}
+
+		current = &commit
+		inHeader = false
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read log: %w", err)
+	}
+
+	if inHeader {
+		return errors.New("unexpected end of log")
+	}
+
+	if current != nil {
+		return handler(*current)
+	}
+
+	return nil
+}
+
+func parseHeader(header string) (Commit, error) {
+	parts := strings.SplitN(header, fieldSeparator, 4)
+	if len(parts) != 4 {
+		return Commit{}, fmt.Errorf("unexpected log header `%s`", header)
+	}
+
+	date, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return Commit{}, fmt.Errorf("parse date of `%s`: %w", parts[0], err)
+	}
+
+	// the subject is the first line up to its last separator, it can contain one
+	firstLine, _, _ := strings.Cut(parts[3], "\n")
+
+	index := strings.LastIndex(firstLine, fieldSeparator)
+	if index == -1 {
+		return Commit{}, fmt.Errorf("unexpected log header `%s`", header)
+	}
+
+	return Commit{
+		Hash:    parts[0],
+		Date:    date,
+		Source:  parts[2],
+		Subject: parts[3][:index],
+		Body:    strings.TrimSpace(parts[3][index+len(fieldSeparator):]),
+	}, nil
+}
+
+// Remote returns the host and the name of the repository from the push URL of its first remote, e.g. `github.com` and `vibioh/herodote`
+func Remote(ctx context.Context, path string) (string, string, error) {
+	output, err := exec.CommandContext(ctx, "git", "-C", path, "remote").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("list remotes: %w", err)
+	}
+
+	name, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	if len(name) == 0 {
+		return "", "", errors.New("repository has no remote")
+	}
+
+	output, err = exec.CommandContext(ctx, "git", "-C", path, "remote", "get-url", "--push", name).Output()
+	if err != nil {
+		return "", "", fmt.Errorf("get url of `%s`: %w", name, err)
+	}
+
+	return parseRemote(strings.TrimSpace(string(output)))
+}
+
+func parseRemote(url string) (string, string, error) {
+	if matches := httpRemoteRegex.FindStringSubmatch(url); matches != nil {
+		return matches[1], matches[2], nil
+	}
+
+	if matches := sshRemoteRegex.FindStringSubmatch(url); matches != nil {
+		return matches[1], matches[2], nil
+	}
+
+	return "", "", fmt.Errorf("unable to parse remote url `%s`", url)
+}
@@ -0,0 +1,146 @@
+package git
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLog(t *testing.T) {
+	cases := map[string]struct {
+		log     string
+		want    []Commit
+		wantErr bool
+	}{
+		"empty": {
+			"",
+			nil,
+			false,
+		},
+		"commits": {
+			"\x1e1a2bc34d\x1f2023-07-20T08:00:00+02:00\x1frefs/heads/main\x1ffeat(git): Add import\x1f\x1d\n\npkg/git/git.go\nREADME.md\n" +
+				"\x1e5e6f\x1f2023-07-19T06:00:00Z\x1fmain\x1fMerge branch 'main'\x1f\x1d\n" +
+				"\x1e7a8b\x1f2023-07-18T06:00:00Z\x1frelease\x1ffix: Handle \x1f separator\x1fFirst line\n\n(cherry picked from commit 1a2b)\n\x1d\n\nnoté.md\n",
+			[]Commit{
+				{Hash: "1a2bc34d", Date: time.Date(2023, 7, 20, 6, 0, 0, 0, time.UTC), Source: "refs/heads/main", Subject: "feat(git): Add import", Paths: []string{"pkg/git/git.go", "README.md"}},
+				{Hash: "5e6f", Date: time.Date(2023, 7, 19, 6, 0, 0, 0, time.UTC), Source: "main", Subject: "Merge branch 'main'"},
+				{Hash: "7a8b", Date: time.Date(2023, 7, 18, 6, 0, 0, 0, time.UTC), Source: "release", Subject: "fix: Handle \x1f separator", Body: "First line\n\n(cherry picked from commit 1a2b)", Paths: []string{"noté.md"}},
+			},
+			false,
+		},
+		"invalid date": {
+			"\x1e1a2bc34d\x1fyesterday\x1fmain\x1ffeat: Add import\x1f\x1d\n",
+			nil,
+			true,
+		},
+		"truncated": {
+			"\x1e1a2bc34d\x1f2023-07-20T06:00:00Z\x1fmain\x1ffeat: Add import\x1fBody\n",
+			nil,
+			true,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			var got []Commit
+
+			err := parseLog(strings.NewReader(tc.log), func(commit Commit) error {
+				got = append(got, commit)
+				return nil
+			})
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("parseLog() error = %v, wantErr %t", err, tc.wantErr)
+			}
+
+			if len(got) != len(tc.want) {
+				t.Fatalf("parseLog() = %+v, want %+v", got, tc.want)
+			}
+
+			for index, commit := range got {
+				if !commit.Date.Equal(tc.want[index].Date) {
+					t.Errorf("parseLog()[%d] date = %s, want %s", index, commit.Date, tc.want[index].Date)
+				}
+
+				commit.Date = tc.want[index].Date
+				if !reflect.DeepEqual(commit, tc.want[index]) {
+					t.Errorf("parseLog()[%d] = %+v, want %+v", index, commit, tc.want[index])
+				}
+			}
+		})
+	}
+}
+
+func TestParseLogHandlerError(t *testing.T) {
+	stop := errors.New("stop")
+
+	err := parseLog(strings.NewReader("\x1ea\x1f2023-07-20T06:00:00Z\x1fmain\x1ffeat: A\x1f\x1d\n\x1eb\x1f2023-07-20T06:00:00Z\x1fmain\x1ffeat: B\x1f\x1d\n"), func(Commit) error {
+		return stop
+	})
+
+	if !errors.Is(err, stop) {
+		t.Errorf("parseLog() error = %v, want %s", err, stop)
+	}
+}
+
+func TestParseRemote(t *testing.T) {
+	cases := map[string]struct {
+		url            string
+		wantHost       string
+		wantRepository string
+		wantErr        bool
+	}{
+		"ssh": {
+			"git@github.com:ViBiOh/herodote.git",
+			"github.com",
+			"ViBiOh/herodote",
+			false,
+		},
+		"ssh without user": {
+			"gitlab.com:group/subgroup/project",
+			"gitlab.com",
+			"group/subgroup/project",
+			false,
+		},
+		"https": {
+			"https://github.com/ViBiOh/herodote.git",
+			"github.com",
+			"ViBiOh/herodote",
+			false,
+		},
+		"https with credentials and port": {
+			"https://token@git.example.com:8443/org/name/",
+			"git.example.com",
+			"org/name",
+			false,
+		},
+		"ssh scheme": {
+			"ssh://git@github.com/ViBiOh/herodote.git",
+			"github.com",
+			"ViBiOh/herodote",
+			false,
+		},
+		"local path": {
+			"/srv/mirrors/herodote.git",
+			"",
+			"",
+			true,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			host, repository, err := parseRemote(tc.url)
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("parseRemote() error = %v, wantErr %t", err, tc.wantErr)
+			}
+
+			if host != tc.wantHost || repository != tc.wantRepository {
+				t.Errorf("parseRemote() = (`%s`, `%s`), want (`%s`, `%s`)", host, repository, tc.wantHost, tc.wantRepository)
+			}
+		})
+	}
+}
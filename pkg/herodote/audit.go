@@ -0,0 +1,170 @@
+package herodote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/cntxt"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+	"github.com/ViBiOh/httputils/v4/pkg/query"
+)
+
+const (
+	auditPath    = "/audit"
+	auditTimeout = time.Second * 5
+)
+
+type auditKey struct{}
+
+// statusWriter keeps the status of the response, for the audit
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(content []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.ResponseWriter.Write(content)
+}
+
+// startAudit prepares the entry of a write of the token, it's saved in background by the returned func once the response is written
+func (a App) startAudit(w http.ResponseWriter, r *http.Request, token model.Token) (http.ResponseWriter, *http.Request, func()) {
+	entry := &model.AuditEntry{
+		Date:   time.Now(),
+		Actor:  token.Name,
+		Tenant: token.Tenant,
+		IP:     a.clientIP(r),
+		Method: r.Method,
+		Path:   r.URL.Path,
+	}
+
+	writer := &statusWriter{ResponseWriter: w}
+
+	return writer, r.WithContext(context.WithValue(r.Context(), auditKey{}, entry)), func() {
+		entry.Status = writer.status
+		if entry.Status == 0 {
+			entry.Status = http.StatusOK
+		}
+
+		go func(ctx context.Context) {
+			ctx, cancel := context.WithTimeout(ctx, auditTimeout)
+			defer cancel()
+
+			if err := a.storeApp.SaveAudit(ctx, *entry); err != nil {
+				logger.WithField("actor", entry.Actor).WithField("method", entry.Method).WithField("path", entry.Path).Error("save audit: %s", err)
+			}
+		}(cntxt.WithoutDeadline(r.Context()))
+	}
+}
+
+// auditTarget records the repository and the hash changed by the request, if audited
+func auditTarget(ctx context.Context, repository, hash string) {
+	if entry, ok := ctx.Value(auditKey{}).(*model.AuditEntry); ok {
+		entry.Repository = repository
+		entry.Hash = hash
+	}
+}
+
+func (a App) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if token, _ := TokenFromContext(r.Context()); !token.Admin {
+		httperror.Forbidden(w)
+		return
+	}
+
+	if r.Method != http.MethodGet || len(strings.Trim(strings.TrimPrefix(r.URL.Path, auditPath), "/")) != 0 {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	pagination, err := query.ParsePagination(r, model.DefaultPageSize, 100)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	filter, last, err := parseAuditFilter(r, pagination.Last)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	entries, totalCount, err := a.storeApp.ListAudit(r.Context(), filter, pagination.PageSize, last)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	var lastKey string
+	if len(entries) > 0 {
+		lastKey = strconv.FormatUint(entries[len(entries)-1].ID, 10)
+	}
+
+	w.Header().Add("Link", pagination.LinkNextHeader(fmt.Sprintf("%s%s", apiPath, r.URL.Path), r.URL.Query()))
+	httpjson.WritePagination(w, http.StatusOK, pagination.PageSize, totalCount, lastKey, entries)
+}
+
+func parseAuditFilter(r *http.Request, rawLast string) (model.AuditFilter, uint64, error) {
+	params := r.URL.Query()
+
+	filter := model.AuditFilter{
+		Actor:      strings.TrimSpace(params.Get("actor")),
+		Repository: cleanParam(params.Get("repository")),
+		Method:     strings.ToUpper(strings.TrimSpace(params.Get("method"))),
+	}
+
+	var err error
+
+	if filter.After, err = parseAuditDate(params.Get("after")); err != nil {
+		return filter, 0, fmt.Errorf("after: %w", err)
+	}
+
+	if filter.Before, err = parseAuditDate(params.Get("before")); err != nil {
+		return filter, 0, fmt.Errorf("before: %w", err)
+	}
+
+	var last uint64
+	if len(rawLast) != 0 {
+		if last, err = strconv.ParseUint(rawLast, 10, 64); err != nil {
+			return filter, 0, fmt.Errorf("last must be the id of an entry: %w", err)
+		}
+	}
+
+	return filter, last, nil
+}
+
+// parseAuditDate accepts a day or a timestamp
+func parseAuditDate(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) == 0 {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.Parse(time.RFC3339, raw); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse(isoDateLayout, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse date: %w", err)
+	}
+
+	return date, nil
+}
@@ -0,0 +1,141 @@
+package herodote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+// auditStore sends the saved entries to the channel, if any
+type auditStore struct {
+	Store
+	entries chan model.AuditEntry
+}
+
+func (s auditStore) SaveAudit(_ context.Context, entry model.AuditEntry) error {
+	if s.entries != nil {
+		s.entries <- entry
+	}
+
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	cases := map[string]struct {
+		method        string
+		authorization string
+		body          string
+		want          *model.AuditEntry
+	}{
+		"deployment": {
+			http.MethodPost,
+			"secret",
+			`{"repository":"ViBiOh/herodote","environment":"production","hash":"1a2bc34d"}`,
+			&model.AuditEntry{Actor: "admin", IP: "172.16.0.1", Method: http.MethodPost, Path: "/deployments", Repository: "vibioh/herodote", Hash: "1a2bc34d", Status: http.StatusCreated},
+		},
+		"invalid": {
+			http.MethodPost,
+			"secret",
+			`{"repository":"vibioh/herodote"}`,
+			&model.AuditEntry{Actor: "admin", IP: "172.16.0.1", Method: http.MethodPost, Path: "/deployments", Status: http.StatusBadRequest},
+		},
+		"invalid credentials": {
+			http.MethodPost,
+			"guess",
+			`{"repository":"vibioh/herodote","environment":"production","hash":"1a2bc34d"}`,
+			&model.AuditEntry{IP: "172.16.0.1", Method: http.MethodPost, Path: "/deployments", Status: http.StatusUnauthorized},
+		},
+		"read": {
+			http.MethodGet,
+			"secret",
+			"",
+			nil,
+		},
+		"read with invalid credentials": {
+			http.MethodGet,
+			"guess",
+			"",
+			nil,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			entries := make(chan model.AuditEntry, 1)
+			instance := App{storeApp: limitStore{createDeploymentStore{auditStore{entries: entries}}}, secret: "secret", trustedProxies: 1}
+
+			request := httptest.NewRequest(tc.method, "/deployments", strings.NewReader(tc.body))
+			request.Header.Set("Authorization", tc.authorization)
+			request.Header.Set("X-Forwarded-For", "10.0.0.1, 172.16.0.1")
+
+			if tc.method == http.MethodGet {
+				request.URL.Path = "/"
+			}
+
+			instance.Handler().ServeHTTP(httptest.NewRecorder(), request)
+
+			if tc.want == nil {
+				select {
+				case entry := <-entries:
+					t.Errorf("Handler() audited %+v", entry)
+				default:
+				}
+
+				return
+			}
+
+			select {
+			case got := <-entries:
+				got.Date = time.Time{}
+				if got != *tc.want {
+					t.Errorf("Handler() audited %+v, want %+v", got, *tc.want)
+				}
+			case <-time.After(time.Second):
+				t.Error("Handler() didn't audit the request")
+			}
+		})
+	}
+}
+
+func TestHandleAudit(t *testing.T) {
+	cases := map[string]struct {
+		token      model.Token
+		url        string
+		wantStatus int
+	}{
+		"not admin": {
+			model.Token{Name: "ci", Patterns: []string{"*"}},
+			"/audit",
+			http.StatusForbidden,
+		},
+		"invalid date": {
+			model.AdminToken,
+			"/audit?after=yesterday",
+			http.StatusBadRequest,
+		},
+		"invalid last": {
+			model.AdminToken,
+			"/audit?last=abc",
+			http.StatusBadRequest,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			request = request.WithContext(withToken(request.Context(), tc.token))
+
+			writer := httptest.NewRecorder()
+			App{}.handleAudit(writer, request)
+
+			if writer.Code != tc.wantStatus {
+				t.Errorf("handleAudit() = %d, want %d", writer.Code, tc.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package herodote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+)
+
+const (
+	componentsPath = "/components"
+	rewritePath    = "/rewrite"
+)
+
+func (a App) handleComponents(w http.ResponseWriter, r *http.Request) {
+	subPath := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, componentsPath), "/")
+
+	switch {
+	case r.Method == http.MethodGet && len(subPath) == 0:
+		httpjson.WriteArray(w, http.StatusOK, a.vocabulary.Components())
+	case r.Method == http.MethodPost && subPath == rewritePath:
+		if token, _ := TokenFromContext(r.Context()); !token.Admin {
+			httperror.Forbidden(w)
+			return
+		}
+
+		rewrites, err := a.rewriteComponents(r.Context(), r.URL.Query().Has("dryRun"))
+		if httperror.HandleError(w, err) {
+			return
+		}
+
+		httpjson.WriteArray(w, http.StatusOK, rewrites)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// rewriteComponents applies the current normalization rules to every stored component
+func (a App) rewriteComponents(ctx context.Context, dryRun bool) ([]model.ComponentRewrite, error) {
+	// components of every repository are rewritten, private ones included
+	filters, err := a.storeApp.ListFilters(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("list filters: %w", err)
+	}
+
+	var rewrites []model.ComponentRewrite
+
+	for _, component := range filters["component"] {
+		target := a.vocabulary.ResolveComponent(component)
+		if target == component {
+			continue
+		}
+
+		rewrite := model.ComponentRewrite{
+			From: component,
+			To:   target,
+		}
+
+		if !dryRun {
+			if rewrite.Count, err = a.storeApp.RewriteComponent(ctx, component, target); err != nil {
+				return rewrites, fmt.Errorf("rewrite component `%s` to `%s`: %w", component, target, err)
+			}
+		}
+
+		rewrites = append(rewrites, rewrite)
+	}
+
+	return rewrites, nil
+}
@@ -0,0 +1,85 @@
+package herodote
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
+)
+
+type componentStore struct {
+	Store
+	rewritten map[string]string
+}
+
+func (s componentStore) ListFilters(_ context.Context, _ []string) (map[string][]string, error) {
+	return map[string][]string{
+		"component": {"api", "apis", "http-api", "store"},
+	}, nil
+}
+
+func (s componentStore) RewriteComponent(_ context.Context, from, to string) (uint64, error) {
+	s.rewritten[from] = to
+	return 1, nil
+}
+
+func TestRewriteComponents(t *testing.T) {
+	componentsFile := filepath.Join(t.TempDir(), "components.json")
+	if err := os.WriteFile(componentsFile, []byte(`[{"name": "api", "aliases": ["apis", "http-api"]}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := flag.NewFlagSet("rewrite", flag.ContinueOnError)
+	vocabularyConfig := vocabulary.Flags(fs, "")
+	if err := fs.Parse([]string{"-componentsFile", componentsFile}); err != nil {
+		t.Fatal(err)
+	}
+
+	vocabularyApp, err := vocabulary.New(vocabularyConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		dryRun        bool
+		want          []model.ComponentRewrite
+		wantRewritten map[string]string
+	}{
+		"dry run": {
+			true,
+			[]model.ComponentRewrite{{From: "apis", To: "api"}, {From: "http-api", To: "api"}},
+			map[string]string{},
+		},
+		"rewrite": {
+			false,
+			[]model.ComponentRewrite{{From: "apis", To: "api", Count: 1}, {From: "http-api", To: "api", Count: 1}},
+			map[string]string{"apis": "api", "http-api": "api"},
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			store := componentStore{rewritten: make(map[string]string)}
+			instance := App{storeApp: store, vocabulary: vocabularyApp}
+
+			got, gotErr := instance.rewriteComponents(context.Background(), tc.dryRun)
+
+			if gotErr != nil {
+				t.Errorf("rewriteComponents() = `%s`", gotErr)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("rewriteComponents() = %+v, want %+v", got, tc.want)
+			}
+
+			if !reflect.DeepEqual(store.rewritten, tc.wantRewritten) {
+				t.Errorf("rewritten = %+v, want %+v", store.rewritten, tc.wantRewritten)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package herodote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+	"github.com/ViBiOh/httputils/v4/pkg/owasp"
+	"github.com/ViBiOh/httputils/v4/pkg/sha"
+)
+
+const cacheControl = "no-cache"
+
+func (a App) lastModified(r *http.Request) (time.Time, bool) {
+	lastModified, err := a.storeApp.LastModified(r.Context())
+	if err != nil {
+		logger.Warn("last modified: %s", err)
+		return time.Time{}, false
+	}
+
+	return lastModified, true
+}
+
+// computeEtag depends on the tenants of the reader, private repositories change the response
+func computeEtag(lastModified time.Time, r *http.Request) string {
+	return fmt.Sprintf(`W/"%s"`, sha.Stream().Write(lastModified.UnixNano()).WriteString(r.URL.Path).WriteString(r.URL.Query().Encode()).WriteString(visibilityKey(readerVisibility(r.Context()))).Sum()[:16])
+}
+
+func visibilityKey(visibility []string) string {
+	if visibility == nil {
+		return "*"
+	}
+
+	return strings.Join(visibility, ",")
+}
+
+func matchEtag(noneMatch, etag string) bool {
+	for _, value := range strings.Split(noneMatch, ",") {
+		value = strings.TrimSpace(value)
+
+		if value == "*" || strings.TrimPrefix(value, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func notModifiedSince(r *http.Request, lastModified time.Time) bool {
+	modifiedSince, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(modifiedSince)
+}
+
+// isNotModified writes validators of the JSON response and reports if the client already has the current version
+func (a App) isNotModified(w http.ResponseWriter, r *http.Request) bool {
+	lastModified, ok := a.lastModified(r)
+	if !ok {
+		return false
+	}
+
+	etag := computeEtag(lastModified, r)
+
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("Vary", "Authorization, Cookie")
+	w.Header().Set("Etag", etag)
+	w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+
+	if noneMatch := r.Header.Get("If-None-Match"); len(noneMatch) != 0 {
+		return matchEtag(noneMatch, etag)
+	}
+
+	return notModifiedSince(r, lastModified)
+}
+
+// pageVersion is the Etag of the data for the day of now, relative dates of the page change every day
+func pageVersion(lastModified, now time.Time, r *http.Request) string {
+	return sha.Stream().WriteString(computeEtag(lastModified, r)).WriteString(now.Format(time.DateOnly)).Sum()[:16]
+}
+
+// isPageNotModified reports if the client already has the current version of the page.
+// The page embeds the nonce of its Content-Security-Policy, so its Etag is `W/"version-nonce"` and the nonce of the cached page is reused.
+func (a App) isPageNotModified(w http.ResponseWriter, r *http.Request, now time.Time) bool {
+	lastModified, ok := a.lastModified(r)
+	if !ok {
+		return false
+	}
+
+	version := pageVersion(lastModified, now, r)
+	if holder, ok := r.Context().Value(pageVersionKey{}).(*string); ok {
+		*holder = version
+	}
+
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("Vary", "Authorization, Cookie")
+
+	parts := strings.SplitN(r.Header.Get("If-None-Match"), "-", 2)
+	if len(parts) != 2 || strings.TrimPrefix(parts[0], `W/"`) != version {
+		return false
+	}
+
+	nonce := strings.TrimSuffix(parts[1], `"`)
+
+	owasp.WriteNonce(w, nonce)
+	w.Header().Set("Etag", fmt.Sprintf(`W/"%s-%s"`, version, nonce))
+
+	return true
+}
+
+type pageVersionKey struct{}
+
+// PageMiddleware replaces the Etag of rendered pages, hashed by the renderer from their content, with the version of their data
+func PageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, apiPath) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		writer := &pageWriter{ResponseWriter: w}
+		next.ServeHTTP(writer, r.WithContext(context.WithValue(r.Context(), pageVersionKey{}, &writer.version)))
+	})
+}
+
+type pageWriter struct {
+	http.ResponseWriter
+	version string
+}
+
+func (w *pageWriter) WriteHeader(status int) {
+	w.setEtag()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *pageWriter) Write(content []byte) (int, error) {
+	w.setEtag()
+	return w.ResponseWriter.Write(content)
+}
+
+// WriteNonce passes the nonce of the page to the Content-Security-Policy of the owasp middleware
+func (w *pageWriter) WriteNonce(nonce string) {
+	owasp.WriteNonce(w.ResponseWriter, nonce)
+}
+
+func (w *pageWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// setEtag keeps the nonce of the renderer's Etag, the nonce being the one of the page
+func (w *pageWriter) setEtag() {
+	if len(w.version) == 0 {
+		return
+	}
+
+	parts := strings.SplitN(w.Header().Get("Etag"), "-", 2)
+	if len(parts) == 2 {
+		w.Header().Set("Etag", fmt.Sprintf(`W/"%s-%s`, w.version, parts[1]))
+	}
+
+	w.version = ""
+}
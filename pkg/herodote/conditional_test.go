@@ -0,0 +1,219 @@
+package herodote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// stubStore implements the methods needed by tests, others panic
+type stubStore struct {
+	Store
+	lastModified time.Time
+}
+
+func (s stubStore) LastModified(_ context.Context) (time.Time, error) {
+	return s.lastModified, nil
+}
+
+func TestMatchEtag(t *testing.T) {
+	cases := map[string]struct {
+		noneMatch string
+		etag      string
+		want      bool
+	}{
+		"different": {
+			`W/"abc"`,
+			`W/"def"`,
+			false,
+		},
+		"weak": {
+			`"abc"`,
+			`W/"abc"`,
+			true,
+		},
+		"list": {
+			`W/"def", W/"abc"`,
+			`W/"abc"`,
+			true,
+		},
+		"wildcard": {
+			`*`,
+			`W/"abc"`,
+			true,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := matchEtag(tc.noneMatch, tc.etag); got != tc.want {
+				t.Errorf("matchEtag() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsNotModified(t *testing.T) {
+	lastModified := time.Date(2023, 7, 20, 9, 0, 0, 0, time.UTC)
+	instance := App{storeApp: stubStore{lastModified: lastModified}}
+
+	etag := computeEtag(lastModified, httptest.NewRequest(http.MethodGet, "/commits?repository=vibioh/herodote", nil))
+
+	matchingEtag := httptest.NewRequest(http.MethodGet, "/commits?repository=vibioh/herodote", nil)
+	matchingEtag.Header.Set("If-None-Match", etag)
+
+	otherQuery := httptest.NewRequest(http.MethodGet, "/commits?repository=vibioh/ketchup", nil)
+	otherQuery.Header.Set("If-None-Match", etag)
+
+	notModifiedSince := httptest.NewRequest(http.MethodGet, "/commits", nil)
+	notModifiedSince.Header.Set("If-Modified-Since", lastModified.Add(time.Minute).Format(http.TimeFormat))
+
+	modifiedSince := httptest.NewRequest(http.MethodGet, "/commits", nil)
+	modifiedSince.Header.Set("If-Modified-Since", lastModified.Add(-time.Minute).Format(http.TimeFormat))
+
+	cases := map[string]struct {
+		request *http.Request
+		want    bool
+	}{
+		"no condition": {
+			httptest.NewRequest(http.MethodGet, "/commits", nil),
+			false,
+		},
+		"matching etag": {
+			matchingEtag,
+			true,
+		},
+		"other query": {
+			otherQuery,
+			false,
+		},
+		"not modified since": {
+			notModifiedSince,
+			true,
+		},
+		"modified since": {
+			modifiedSince,
+			false,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			writer := httptest.NewRecorder()
+
+			if got := instance.isNotModified(writer, tc.request); got != tc.want {
+				t.Errorf("isNotModified() = %t, want %t", got, tc.want)
+			}
+
+			if got := writer.Header().Get("Last-Modified"); got != lastModified.Format(http.TimeFormat) {
+				t.Errorf("Last-Modified = `%s`, want `%s`", got, lastModified.Format(http.TimeFormat))
+			}
+		})
+	}
+}
+
+func TestIsPageNotModified(t *testing.T) {
+	lastModified := time.Date(2023, 7, 20, 9, 0, 0, 0, time.UTC)
+	now := lastModified.Add(time.Hour)
+	instance := App{storeApp: stubStore{lastModified: lastModified}}
+
+	version := pageVersion(lastModified, now, httptest.NewRequest(http.MethodGet, "/?repository=vibioh/herodote", nil))
+
+	matchingVersion := httptest.NewRequest(http.MethodGet, "/?repository=vibioh/herodote", nil)
+	matchingVersion.Header.Set("If-None-Match", `W/"`+version+`-n0nce"`)
+
+	otherDay := httptest.NewRequest(http.MethodGet, "/?repository=vibioh/herodote", nil)
+	otherDay.Header.Set("If-None-Match", `W/"`+pageVersion(lastModified, now.Add(-dayDuration), otherDay)+`-n0nce"`)
+
+	withoutNonce := httptest.NewRequest(http.MethodGet, "/?repository=vibioh/herodote", nil)
+	withoutNonce.Header.Set("If-None-Match", `W/"`+version+`"`)
+
+	notModifiedSince := httptest.NewRequest(http.MethodGet, "/?repository=vibioh/herodote", nil)
+	notModifiedSince.Header.Set("If-Modified-Since", now.Format(http.TimeFormat))
+
+	cases := map[string]struct {
+		request  *http.Request
+		want     bool
+		wantEtag string
+	}{
+		"no condition": {
+			httptest.NewRequest(http.MethodGet, "/?repository=vibioh/herodote", nil),
+			false,
+			"",
+		},
+		"matching version": {
+			matchingVersion,
+			true,
+			`W/"` + version + `-n0nce"`,
+		},
+		"other day": {
+			otherDay,
+			false,
+			"",
+		},
+		"without nonce": {
+			withoutNonce,
+			false,
+			"",
+		},
+		"not modified since": {
+			notModifiedSince,
+			false,
+			"",
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			writer := httptest.NewRecorder()
+
+			if got := instance.isPageNotModified(writer, tc.request, now); got != tc.want {
+				t.Errorf("isPageNotModified() = %t, want %t", got, tc.want)
+			}
+
+			if got := writer.Header().Get("Etag"); got != tc.wantEtag {
+				t.Errorf("Etag = `%s`, want `%s`", got, tc.wantEtag)
+			}
+		})
+	}
+}
+
+func TestPageMiddleware(t *testing.T) {
+	lastModified := time.Date(2023, 7, 20, 9, 0, 0, 0, time.UTC)
+	now := lastModified.Add(time.Hour)
+	instance := App{storeApp: stubStore{lastModified: lastModified}}
+
+	// renders like the renderer, with the Etag of the content
+	handler := PageMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		instance.isPageNotModified(w, r, now)
+		w.Header().Add("Etag", `W/"c0ntent-n0nce"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	cases := map[string]struct {
+		url  string
+		want string
+	}{
+		"page": {
+			"/?repository=vibioh/herodote",
+			`W/"` + pageVersion(lastModified, now, httptest.NewRequest(http.MethodGet, "/?repository=vibioh/herodote", nil)) + `-n0nce"`,
+		},
+		"api": {
+			"/api/commits",
+			`W/"c0ntent-n0nce"`,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			writer := httptest.NewRecorder()
+			handler.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if got := writer.Header().Get("Etag"); got != tc.want {
+				t.Errorf("Etag = `%s`, want `%s`", got, tc.want)
+			}
+		})
+	}
+}
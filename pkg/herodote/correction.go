@@ -0,0 +1,142 @@
+package herodote
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
+)
+
+var errNoCommitIdentifier = errors.New("commit's repository and hash are required (e.g. `/commits/vibioh/herodote/1a2bc34d`)")
+
+type deletedCommits struct {
+	Repository string `json:"repository"`
+	Count      uint64 `json:"count"`
+}
+
+// parseCommitPath extracts repository and hash from a path like `/commits/vibioh/herodote/1a2bc34d`
+func parseCommitPath(urlPath string) (string, string, error) {
+	commitPath := strings.Trim(strings.TrimPrefix(urlPath, commitsPath), "/")
+
+	index := strings.LastIndex(commitPath, "/")
+	if index <= 0 || index == len(commitPath)-1 {
+		return "", "", httpModel.WrapInvalid(errNoCommitIdentifier)
+	}
+
+	return strings.ToLower(commitPath[:index]), strings.ToLower(commitPath[index+1:]), nil
+}
+
+func (a App) handlePatchCommit(w http.ResponseWriter, r *http.Request) {
+	repository, hash, err := parseCommitPath(r.URL.Path)
+	if httperror.HandleError(w, err) {
+		return
+	}
+
+	var patch model.CommitPatch
+	if err = httpjson.Parse(r, &patch); err != nil {
+		writeParseError(w, err)
+		return
+	}
+
+	if patch.Type != nil {
+		var commitType string
+		if commitType, err = a.vocabulary.ResolveType(*patch.Type); err != nil {
+			httperror.BadRequest(w, err)
+			return
+		}
+
+		patch.Type = &commitType
+	}
+
+	if patch.Component != nil {
+		component := a.vocabulary.ResolveComponent(*patch.Component)
+		patch.Component = &component
+	}
+
+	auditTarget(r.Context(), repository, hash)
+
+	if httperror.HandleError(w, a.checkRepositoryAccess(r.Context(), repository)) {
+		return
+	}
+
+	commit, err := a.storeApp.GetCommit(r.Context(), repository, hash)
+	if httperror.HandleError(w, err) {
+		return
+	}
+
+	// repository may have been renamed since
+	repository = commit.Repository
+
+	commit = patch.Apply(commit)
+	commit.References = a.vocabulary.ExtractReferences(commit.Content)
+	if err = commit.Check(a.maxContentLength); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	if httperror.HandleError(w, a.checkRepositoryAccess(r.Context(), commit.Repository)) {
+		return
+	}
+
+	if err = a.storeApp.UpdateCommit(r.Context(), repository, hash, commit); err != nil {
+		httperror.HandleError(w, fmt.Errorf("update commit for `%s` with hash `%s`: %w", repository, hash, err))
+		return
+	}
+
+	httpjson.Write(w, http.StatusOK, a.vocabulary.LinkReference(commit))
+}
+
+func (a App) handleDeleteCommits(w http.ResponseWriter, r *http.Request) {
+	if len(strings.Trim(strings.TrimPrefix(r.URL.Path, commitsPath), "/")) == 0 {
+		a.handleDeleteRepository(w, r)
+		return
+	}
+
+	repository, hash, err := parseCommitPath(r.URL.Path)
+	if httperror.HandleError(w, err) {
+		return
+	}
+
+	auditTarget(r.Context(), repository, hash)
+
+	if httperror.HandleError(w, a.checkRepositoryAccess(r.Context(), repository)) {
+		return
+	}
+
+	if err = a.storeApp.DeleteCommit(r.Context(), repository, hash); err != nil {
+		httperror.HandleError(w, fmt.Errorf("delete commit for `%s` with hash `%s`: %w", repository, hash, err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a App) handleDeleteRepository(w http.ResponseWriter, r *http.Request) {
+	repository := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("repository")))
+	if len(repository) == 0 {
+		httperror.BadRequest(w, errors.New("repository is required (e.g. `?repository=vibioh/herodote`)"))
+		return
+	}
+
+	auditTarget(r.Context(), repository, "")
+
+	if httperror.HandleError(w, a.checkRepositoryAccess(r.Context(), repository)) {
+		return
+	}
+
+	count, err := a.storeApp.DeleteRepository(r.Context(), repository)
+	if err != nil {
+		httperror.InternalServerError(w, fmt.Errorf("delete commits of `%s`: %w", repository, err))
+		return
+	}
+
+	httpjson.Write(w, http.StatusOK, deletedCommits{
+		Repository: repository,
+		Count:      count,
+	})
+}
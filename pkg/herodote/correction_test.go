@@ -0,0 +1,53 @@
+package herodote
+
+import (
+	"testing"
+)
+
+func TestParseCommitPath(t *testing.T) {
+	cases := map[string]struct {
+		urlPath        string
+		wantRepository string
+		wantHash       string
+		wantErr        bool
+	}{
+		"empty": {
+			"/commits",
+			"",
+			"",
+			true,
+		},
+		"no hash": {
+			"/commits/vibioh/",
+			"",
+			"",
+			true,
+		},
+		"simple": {
+			"/commits/ViBiOh/herodote/1A2bc34d",
+			"vibioh/herodote",
+			"1a2bc34d",
+			false,
+		},
+		"nested": {
+			"/commits/group/subgroup/project/1a2bc34d/",
+			"group/subgroup/project",
+			"1a2bc34d",
+			false,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			gotRepository, gotHash, gotErr := parseCommitPath(tc.urlPath)
+
+			if (gotErr != nil) != tc.wantErr {
+				t.Errorf("parseCommitPath() = `%s`, want error %t", gotErr, tc.wantErr)
+			}
+
+			if gotRepository != tc.wantRepository || gotHash != tc.wantHash {
+				t.Errorf("parseCommitPath() = (`%s`, `%s`), want (`%s`, `%s`)", gotRepository, gotHash, tc.wantRepository, tc.wantHash)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package herodote
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/query"
+)
+
+const (
+	deploymentsPath = "/deployments"
+	comparePath     = "/compare"
+	changesPath     = "/changes"
+)
+
+func (a App) handleDeployments(w http.ResponseWriter, r *http.Request) {
+	subPath := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, deploymentsPath), "/")
+
+	switch {
+	case r.Method == http.MethodPost && len(subPath) == 0:
+		a.handleCreateDeployment(w, r)
+	case r.Method == http.MethodGet && len(subPath) == 0:
+		a.handleListDeployments(w, r)
+	case r.Method == http.MethodGet && subPath == comparePath:
+		a.handleCompareDeployments(w, r)
+	case r.Method == http.MethodGet && subPath == changesPath:
+		a.handleDeploymentChanges(w, r)
+	case len(subPath) == 0 || subPath == comparePath || subPath == changesPath:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	default:
+		httperror.NotFound(w)
+	}
+}
+
+func (a App) handleCreateDeployment(w http.ResponseWriter, r *http.Request) {
+	var deployment model.Deployment
+	if err := httpjson.Parse(r, &deployment); err != nil {
+		writeParseError(w, err)
+		return
+	}
+
+	deployment = deployment.Sanitize()
+	if err := deployment.Check(); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	auditTarget(r.Context(), deployment.Repository, deployment.Hash)
+
+	if err := a.checkRepositoryAccess(r.Context(), deployment.Repository); err != nil {
+		httperror.HandleError(w, err)
+		return
+	}
+
+	if deployment.Date.IsZero() {
+		deployment.Date = time.Now()
+	}
+
+	id, err := a.storeApp.CreateDeployment(r.Context(), deployment)
+	if err != nil {
+		httperror.InternalServerError(w, fmt.Errorf("create deployment of `%s` to `%s`: %w", deployment.Repository, deployment.Environment, err))
+		return
+	}
+
+	deployment.ID = id
+
+	httpjson.Write(w, http.StatusCreated, deployment)
+}
+
+func (a App) handleListDeployments(w http.ResponseWriter, r *http.Request) {
+	pagination, err := query.ParsePagination(r, model.DefaultPageSize, 100)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	params := r.URL.Query()
+
+	deployments, err := a.storeApp.ListDeployments(r.Context(), cleanParam(params.Get("repository")), cleanParam(params.Get("environment")), cleanParam(params.Get("status")), readerVisibility(r.Context()), pagination.PageSize)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	httpjson.WriteArray(w, http.StatusOK, deployments)
+}
+
+// handleCompareDeployments lists commits deployed to the environment but not yet to the base environment
+func (a App) handleCompareDeployments(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	repository := cleanParam(params.Get("repository"))
+	environment := cleanParam(params.Get("environment"))
+	base := cleanParam(params.Get("base"))
+
+	if len(repository) == 0 || len(environment) == 0 || len(base) == 0 {
+		httperror.BadRequest(w, errors.New("repository, environment and base are required"))
+		return
+	}
+
+	deployed, err := a.lastDeployments(r, repository, environment, 1)
+	if err != nil {
+		httperror.HandleError(w, err)
+		return
+	}
+
+	baseDeployed, err := a.storeApp.ListDeployments(r.Context(), repository, base, model.DeploymentSuccess, readerVisibility(r.Context()), 1)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	var after string
+	if len(baseDeployed) != 0 {
+		after = searchBound(baseDeployed[0].CommitDate)
+	}
+
+	a.writeDeployedCommits(w, r, repository, deployed[0], after)
+}
+
+// handleDeploymentChanges lists commits of the last deployment of the environment, since the previous one
+func (a App) handleDeploymentChanges(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	repository := cleanParam(params.Get("repository"))
+	environment := cleanParam(params.Get("environment"))
+
+	if len(repository) == 0 || len(environment) == 0 {
+		httperror.BadRequest(w, errors.New("repository and environment are required"))
+		return
+	}
+
+	deployed, err := a.lastDeployments(r, repository, environment, 2)
+	if err != nil {
+		httperror.HandleError(w, err)
+		return
+	}
+
+	var after string
+	if len(deployed) > 1 {
+		after = searchBound(deployed[1].CommitDate)
+	}
+
+	a.writeDeployedCommits(w, r, repository, deployed[0], after)
+}
+
+func (a App) lastDeployments(r *http.Request, repository, environment string, count uint) ([]model.Deployment, error) {
+	deployments, err := a.storeApp.ListDeployments(r.Context(), repository, environment, model.DeploymentSuccess, readerVisibility(r.Context()), count)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deployments) == 0 {
+		return nil, httpModel.WrapNotFound(fmt.Errorf("no successful deployment of `%s` to `%s`", repository, environment))
+	}
+
+	return deployments, nil
+}
+
+// writeDeployedCommits writes commits matching the search, up to the deployed one and after the given bound
+func (a App) writeDeployedCommits(w http.ResponseWriter, r *http.Request, repository string, deployed model.Deployment, after string) {
+	pagination, err := query.ParsePagination(r, model.DefaultPageSize, 100)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	search, err := a.parseSearch(r.Context(), r.URL.Query())
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	search.filters["repository"] = []string{repository}
+
+	// dates of commits are stored to the second, the deployed commit is included
+	before := searchBound(deployed.CommitDate.Add(time.Second))
+
+	commits, err := a.storeApp.SearchCommit(r.Context(), search.query, search.filters, before, after, pagination.PageSize, pagination.Last)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	commits.Commits = a.vocabulary.LinkReferences(commits.Commits)
+
+	var last string
+	if len(commits.Commits) > 0 {
+		last = commits.Commits[len(commits.Commits)-1].Date.String()
+	}
+
+	w.Header().Add("Link", pagination.LinkNextHeader(fmt.Sprintf("%s%s", apiPath, r.URL.Path), r.URL.Query()))
+	httpjson.WritePagination(w, http.StatusOK, pagination.PageSize, commits.TotalCount, last, commits.Commits)
+}
+
+// searchBound formats a date to the second, as a bound of the search
+func searchBound(date time.Time) string {
+	return date.UTC().Format(time.RFC3339)
+}
+
+// deploymentsByCommit indexes deployments by repository and hash of their commit, for the timeline
+func deploymentsByCommit(deployments []model.Deployment) map[string][]model.Deployment {
+	output := make(map[string][]model.Deployment)
+
+	for _, deployment := range deployments {
+		if len(deployment.Commit) == 0 {
+			continue
+		}
+
+		key := deploymentKey(deployment.Repository, deployment.Commit)
+		output[key] = append(output[key], deployment)
+	}
+
+	return output
+}
+
+func deploymentKey(repository, hash string) string {
+	return repository + "@" + hash
+}
+
+func cleanParam(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
@@ -0,0 +1,175 @@
+package herodote
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
+)
+
+type deploymentStore struct {
+	Store
+	bounds      *[2]string
+	deployments []model.Deployment
+}
+
+// ListDeployments returns the deployments of the environment, they are declared most recent first
+func (s deploymentStore) ListDeployments(_ context.Context, repository, environment, _ string, _ []string, pageSize uint) ([]model.Deployment, error) {
+	var output []model.Deployment
+
+	for _, deployment := range s.deployments {
+		if deployment.Repository == repository && deployment.Environment == environment && uint(len(output)) < pageSize {
+			output = append(output, deployment)
+		}
+	}
+
+	return output, nil
+}
+
+func (s deploymentStore) SearchCommit(_ context.Context, _ string, _ map[string][]string, before, after string, _ uint, _ string) (model.CommitsList, error) {
+	*s.bounds = [2]string{before, after}
+
+	return model.CommitsList{}, nil
+}
+
+func TestHandleDeployments(t *testing.T) {
+	fs := flag.NewFlagSet("deployments", flag.ContinueOnError)
+	vocabularyConfig := vocabulary.Flags(fs, "")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	vocabularyApp, err := vocabulary.New(vocabularyConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deployments := []model.Deployment{
+		{Repository: "vibioh/herodote", Environment: "production", Hash: "c3", CommitDate: time.Date(2023, 7, 22, 8, 0, 0, 0, time.FixedZone("CEST", 7200))},
+		{Repository: "vibioh/herodote", Environment: "staging", Hash: "b2", CommitDate: time.Date(2023, 7, 21, 6, 0, 0, 0, time.UTC)},
+		{Repository: "vibioh/herodote", Environment: "production", Hash: "a1", CommitDate: time.Date(2023, 7, 20, 6, 0, 0, 0, time.UTC)},
+	}
+
+	cases := map[string]struct {
+		url        string
+		wantStatus int
+		wantBounds [2]string
+	}{
+		"compare": {
+			"/deployments/compare?repository=vibioh/herodote&environment=production&base=staging",
+			http.StatusOK,
+			[2]string{"2023-07-22T06:00:01Z", "2023-07-21T06:00:00Z"},
+		},
+		"compare never deployed base": {
+			"/deployments/compare?repository=vibioh/herodote&environment=staging&base=qa",
+			http.StatusOK,
+			[2]string{"2023-07-21T06:00:01Z", ""},
+		},
+		"compare never deployed": {
+			"/deployments/compare?repository=vibioh/herodote&environment=qa&base=staging",
+			http.StatusNotFound,
+			[2]string{},
+		},
+		"compare without base": {
+			"/deployments/compare?repository=vibioh/herodote&environment=production",
+			http.StatusBadRequest,
+			[2]string{},
+		},
+		"changes": {
+			"/deployments/changes?repository=vibioh/herodote&environment=production",
+			http.StatusOK,
+			[2]string{"2023-07-22T06:00:01Z", "2023-07-20T06:00:00Z"},
+		},
+		"changes of first deployment": {
+			"/deployments/changes?repository=vibioh/herodote&environment=staging",
+			http.StatusOK,
+			[2]string{"2023-07-21T06:00:01Z", ""},
+		},
+		"changes with invalid filter": {
+			"/deployments/changes?repository=vibioh/herodote&environment=production&after=yesterday",
+			http.StatusBadRequest,
+			[2]string{},
+		},
+		"unknown view": {
+			"/deployments/diff",
+			http.StatusNotFound,
+			[2]string{},
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			var bounds [2]string
+			instance := App{storeApp: deploymentStore{bounds: &bounds, deployments: deployments}, vocabulary: vocabularyApp}
+
+			writer := httptest.NewRecorder()
+			instance.Handler().ServeHTTP(writer, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if writer.Code != tc.wantStatus {
+				t.Errorf("handleDeployments() = %d, want %d", writer.Code, tc.wantStatus)
+			}
+
+			if bounds != tc.wantBounds {
+				t.Errorf("handleDeployments() bounds = %v, want %v", bounds, tc.wantBounds)
+			}
+		})
+	}
+}
+
+func TestHandleCreateDeployment(t *testing.T) {
+	cases := map[string]struct {
+		body       string
+		wantStatus int
+		want       string
+	}{
+		"valid": {
+			`{"repository":"ViBiOh/herodote","environment":"Production","hash":"1a2bc34d","date":"2023-07-22T06:00:00Z"}`,
+			http.StatusCreated,
+			`{"date":"2023-07-22T06:00:00Z","repository":"vibioh/herodote","environment":"production","hash":"1a2bc34d","status":"success","id":0}`,
+		},
+		"unknown status": {
+			`{"repository":"vibioh/herodote","environment":"production","hash":"1a2bc34d","status":"done"}`,
+			http.StatusBadRequest,
+			"deployment's status must be `pending`, `success` or `failure`",
+		},
+		"missing environment": {
+			`{"repository":"vibioh/herodote","hash":"1a2bc34d"}`,
+			http.StatusBadRequest,
+			"deployment's environment is required (e.g. `production`)",
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			instance := App{storeApp: createDeploymentStore{}, secret: "secret"}
+
+			request := httptest.NewRequest(http.MethodPost, "/deployments", strings.NewReader(tc.body))
+			request.Header.Set("Authorization", "secret")
+
+			writer := httptest.NewRecorder()
+			instance.Handler().ServeHTTP(writer, request)
+
+			if writer.Code != tc.wantStatus {
+				t.Errorf("handleCreateDeployment() = %d, want %d", writer.Code, tc.wantStatus)
+			}
+
+			if got := strings.TrimSpace(writer.Body.String()); got != tc.want {
+				t.Errorf("handleCreateDeployment() = `%s`, want `%s`", got, tc.want)
+			}
+		})
+	}
+}
+
+type createDeploymentStore struct {
+	auditStore
+}
+
+func (createDeploymentStore) CreateDeployment(_ context.Context, _ model.Deployment) (uint64, error) {
+	return 0, nil
+}
@@ -0,0 +1,155 @@
+package herodote
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+)
+
+const (
+	exportPath = "/export"
+
+	csvFormat    = "csv"
+	ndjsonFormat = "ndjson"
+
+	// exportFlushSize is the number of commits sent together to the client
+	exportFlushSize = 1000
+)
+
+type exportWriter interface {
+	Write(model.CommitRecord) error
+	Flush() error
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w ndjsonWriter) Write(commit model.CommitRecord) error {
+	return w.encoder.Encode(commit)
+}
+
+func (w ndjsonWriter) Flush() error {
+	return nil
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (w csvWriter) Write(commit model.CommitRecord) error {
+	return w.writer.Write(commit.CSV())
+}
+
+func (w csvWriter) Flush() error {
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
+
+func newExportWriter(format string, w io.Writer) (exportWriter, string, error) {
+	switch format {
+	case "", ndjsonFormat:
+		return ndjsonWriter{encoder: json.NewEncoder(w)}, "application/x-ndjson", nil
+	case csvFormat:
+		writer := csvWriter{writer: csv.NewWriter(w)}
+
+		return writer, "text/csv; charset=utf-8", writer.writer.Write(model.RecordHeader)
+	default:
+		return nil, "", fmt.Errorf("export format `%s` is unknown, expected `%s` or `%s`", format, ndjsonFormat, csvFormat)
+	}
+}
+
+// handleExport streams every commit matching the search, without pagination, in the order of saving
+func (a App) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	search, err := a.parseSearch(r.Context(), r.URL.Query())
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if len(format) == 0 {
+		format = ndjsonFormat
+	}
+
+	writer, contentType, err := newExportWriter(format, w)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	// an export outlives the write timeout of the server
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	var count uint64
+
+	flush := func() error {
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+
+		if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+
+		return nil
+	}
+
+	// headers are sent with the first commit, an error on the first batch is still reported with a status
+	writeHeaders := func() {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="herodote.%s"`, format))
+		w.Header().Set("Cache-Control", cacheControl)
+		w.WriteHeader(http.StatusOK)
+	}
+
+	err = a.storeApp.ExportCommits(r.Context(), search.query, search.filters, search.before, search.after, func(event model.CommitEvent) error {
+		if count == 0 {
+			writeHeaders()
+		}
+
+		if err := writer.Write(model.NewCommitRecord(event)); err != nil {
+			return err
+		}
+
+		if count++; count%exportFlushSize == 0 {
+			return flush()
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if count == 0 {
+			httperror.InternalServerError(w, fmt.Errorf("export: %w", err))
+		} else {
+			logger.Error("export interrupted after %d commits: %s", count, err)
+		}
+
+		return
+	}
+
+	if count == 0 {
+		writeHeaders()
+	}
+
+	if err = flush(); err != nil {
+		logger.Error("flush export: %s", err)
+	}
+}
@@ -0,0 +1,110 @@
+package herodote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+type exportStore struct {
+	Store
+	err    error
+	events []model.CommitEvent
+}
+
+func (s exportStore) ExportCommits(_ context.Context, _ string, _ map[string][]string, _, _ string, handler func(model.CommitEvent) error) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	for _, event := range s.events {
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestHandleExport(t *testing.T) {
+	events := []model.CommitEvent{
+		{ID: 1, Commit: model.Commit{Date: time.Date(2023, 7, 20, 8, 0, 0, 0, time.FixedZone("CEST", 7200)), Repository: "vibioh/herodote", Remote: "github.com", Hash: "a1", Type: "feat", Component: "api", Content: "Add export, fixes #12", Paths: []string{"pkg/herodote", "README.md"}, References: model.NewReferences([]string{"#12"}), Branches: []string{"main", "release/1.x"}}},
+		{ID: 2, Commit: model.Commit{Date: time.Date(2023, 7, 21, 6, 0, 0, 0, time.UTC), Repository: "vibioh/ketchup", Remote: "github.com", Hash: "b2", Type: "fix", Content: "Quote \"values\", with comma", PickedFrom: "a1", Tenant: "acme", Breaking: true}},
+	}
+
+	cases := map[string]struct {
+		url             string
+		err             error
+		wantStatus      int
+		wantContentType string
+		want            string
+	}{
+		"ndjson": {
+			"/export?repository=vibioh/herodote",
+			nil,
+			http.StatusOK,
+			"application/x-ndjson",
+			`{"date":"2023-07-20T06:00:00Z","repository":"vibioh/herodote","remote":"github.com","hash":"a1","type":"feat","component":"api","content":"Add export, fixes #12","pickedFrom":"","tenant":"","paths":["pkg/herodote","README.md"],"refs":["#12"],"branches":["main","release/1.x"],"id":1,"breaking":false,"revert":false}
+{"date":"2023-07-21T06:00:00Z","repository":"vibioh/ketchup","remote":"github.com","hash":"b2","type":"fix","component":"","content":"Quote \"values\", with comma","pickedFrom":"a1","tenant":"acme","paths":[],"refs":[],"branches":[],"id":2,"breaking":true,"revert":false}
+`,
+		},
+		"csv": {
+			"/export?format=CSV",
+			nil,
+			http.StatusOK,
+			"text/csv; charset=utf-8",
+			`id,date,repository,remote,hash,type,component,breaking,revert,content,paths,refs,branches,pickedFrom,tenant
+1,2023-07-20T06:00:00Z,vibioh/herodote,github.com,a1,feat,api,false,false,"Add export, fixes #12",pkg/herodote;README.md,#12,main;release/1.x,,
+2,2023-07-21T06:00:00Z,vibioh/ketchup,github.com,b2,fix,,true,false,"Quote ""values"", with comma",,,,a1,acme
+`,
+		},
+		"unknown format": {
+			"/export?format=parquet",
+			nil,
+			http.StatusBadRequest,
+			"text/plain; charset=utf-8",
+			"export format `parquet` is unknown, expected `ndjson` or `csv`\n",
+		},
+		"invalid date": {
+			"/export?before=yesterday",
+			nil,
+			http.StatusBadRequest,
+			"text/plain; charset=utf-8",
+			"",
+		},
+		"store error": {
+			"/export",
+			errors.New("timeout"),
+			http.StatusInternalServerError,
+			"text/plain; charset=utf-8",
+			"",
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			store := exportStore{events: events, err: tc.err}
+			app := App{storeApp: store}
+
+			writer := httptest.NewRecorder()
+			app.handleExport(writer, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if writer.Code != tc.wantStatus {
+				t.Errorf("handleExport() status = %d, want %d", writer.Code, tc.wantStatus)
+			}
+
+			if got := writer.Header().Get("Content-Type"); got != tc.wantContentType {
+				t.Errorf("handleExport() content-type = `%s`, want `%s`", got, tc.wantContentType)
+			}
+
+			if len(tc.want) != 0 && writer.Body.String() != tc.want {
+				t.Errorf("handleExport() = `%s`, want `%s`", writer.Body.String(), tc.want)
+			}
+		})
+	}
+}
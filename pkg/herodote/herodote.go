@@ -12,6 +12,9 @@ import (
 
 	"github.com/ViBiOh/flags"
 	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/oidc"
+	"github.com/ViBiOh/herodote/pkg/ratelimit"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
 	"github.com/ViBiOh/httputils/v4/pkg/httperror"
 	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
 	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
@@ -32,30 +35,77 @@ var ErrAuthentificationFailed = errors.New("invalid secret provided")
 
 type Store interface {
 	Enabled() bool
-	ListFilters(context.Context) (map[string][]string, error)
+	ListFilters(ctx context.Context, visibility []string) (map[string][]string, error)
+	LastModified(context.Context) (time.Time, error)
 	SearchCommit(ctx context.Context, query string, filters map[string][]string, before, after string, pageSize uint, last string) (model.CommitsList, error)
-	SaveCommit(context.Context, model.Commit) error
+	ExportCommits(ctx context.Context, query string, filters map[string][]string, before, after string, handler func(model.CommitEvent) error) error
+	SaveCommit(context.Context, model.Commit) (created bool, err error)
+	GetCommit(ctx context.Context, repository, hash string) (model.Commit, error)
+	FindCommit(ctx context.Context, repository, hash string) (model.Commit, error)
+	UpdateCommit(ctx context.Context, repository, hash string, commit model.Commit) error
+	DeleteCommit(ctx context.Context, repository, hash string) error
+	DeleteRepository(ctx context.Context, repository string) (uint64, error)
+	RewriteComponent(ctx context.Context, from, to string) (uint64, error)
+	ListAliases(context.Context) ([]model.RepositoryAlias, error)
+	RenameRepository(context.Context, model.RepositoryAlias) (uint64, error)
+	ListTokens(context.Context) ([]model.Token, error)
+	GetTokenByHash(ctx context.Context, hash string) (model.Token, string, error)
+	CreateToken(ctx context.Context, token model.Token, hash string) error
+	DeleteToken(ctx context.Context, name string) error
+	TouchToken(ctx context.Context, name string) error
+	ListWebhooks(context.Context) ([]model.Webhook, error)
+	CreateWebhook(context.Context, model.Webhook) error
+	DeleteWebhook(ctx context.Context, name string) error
+	ListWebhookFailures(ctx context.Context, name string, pageSize uint) ([]model.WebhookFailure, error)
+	ListSubscriptions(ctx context.Context, owner string) ([]model.Subscription, error)
+	CreateSubscription(context.Context, model.Subscription) (uint64, error)
+	DeleteSubscription(ctx context.Context, id uint64, owner string) error
+	StreamCommits() (<-chan model.CommitEvent, func())
+	ListCommitEvents(ctx context.Context, after uint64, pageSize uint) ([]model.CommitEvent, error)
+	CreateDeployment(context.Context, model.Deployment) (uint64, error)
+	ListDeployments(ctx context.Context, repository, environment, status string, visibility []string, pageSize uint) ([]model.Deployment, error)
+	ListCurrentDeployments(ctx context.Context, repositories []string) ([]model.Deployment, error)
+	ListPrivateRepositories(context.Context) ([]model.PrivateRepository, error)
+	PrivateRepositories(context.Context) (model.PrivateRepositories, error)
+	SetPrivateRepository(context.Context, model.PrivateRepository) error
+	DeletePrivateRepository(ctx context.Context, repository string) error
+	SaveAudit(context.Context, model.AuditEntry) error
+	ListAudit(ctx context.Context, filter model.AuditFilter, pageSize uint, last uint64) ([]model.AuditEntry, uint, error)
 }
 
 type App struct {
-	tracer     trace.Tracer
-	apiHandler http.Handler
-	colors     map[string]string
-	storeApp   Store
-	secret     string
+	tracer           trace.Tracer
+	apiHandler       http.Handler
+	colors           map[string]string
+	storeApp         Store
+	done             <-chan struct{}
+	secret           string
+	vocabulary       vocabulary.App
+	sessions         oidc.App
+	limiter          ratelimit.App
+	maxBodySize      int64
+	maxContentLength uint
+	trustedProxies   uint
 }
 
 type Config struct {
-	secret *string
+	secret           *string
+	maxBodySize      *int
+	maxContentLength *uint
+	trustedProxies   *uint
 }
 
 func Flags(fs *flag.FlagSet, prefix string) Config {
 	return Config{
-		secret: flags.New("HttpSecret", "HTTP Secret Key for Update").Prefix(prefix).DocPrefix("herodote").String(fs, "", nil),
+		secret:           flags.New("HttpSecret", "HTTP Secret Key for Update").Prefix(prefix).DocPrefix("herodote").String(fs, "", nil),
+		maxBodySize:      flags.New("MaxBodySize", "Maximum size of a request body, in bytes").Prefix(prefix).DocPrefix("herodote").Int(fs, 1<<20, nil),
+		maxContentLength: flags.New("MaxContentLength", "Maximum number of characters of a commit's content, 0 to disable").Prefix(prefix).DocPrefix("herodote").Uint(fs, model.DefaultMaxContentLength, nil),
+		trustedProxies:   flags.New("TrustedProxies", "Reverse proxies in front of the API appending to X-Forwarded-For, the client IP is the connection's one if 0").Prefix(prefix).DocPrefix("herodote").Uint(fs, 0, nil),
 	}
 }
 
-func New(config Config, storeApp Store, tracer trace.Tracer) (App, error) {
+// New creates the app, streams of commits are ended when done is closed
+func New(config Config, storeApp Store, vocabularyApp vocabulary.App, sessionsApp oidc.App, limiterApp ratelimit.App, done <-chan struct{}, tracer trace.Tracer) (App, error) {
 	if len(*config.secret) == 0 {
 		return App{}, errors.New("http secret is required")
 	}
@@ -65,10 +115,17 @@ func New(config Config, storeApp Store, tracer trace.Tracer) (App, error) {
 	}
 
 	app := App{
-		secret:   *config.secret,
-		storeApp: storeApp,
-		tracer:   tracer,
-		colors:   make(map[string]string),
+		secret:           *config.secret,
+		storeApp:         storeApp,
+		vocabulary:       vocabularyApp,
+		sessions:         sessionsApp,
+		limiter:          limiterApp,
+		maxBodySize:      int64(*config.maxBodySize),
+		maxContentLength: *config.maxContentLength,
+		trustedProxies:   *config.trustedProxies,
+		done:             done,
+		tracer:           tracer,
+		colors:           make(map[string]string),
 	}
 
 	app.apiHandler = http.StripPrefix(apiPath, app.Handler())
@@ -78,9 +135,48 @@ func New(config Config, storeApp Store, tracer trace.Tracer) (App, error) {
 
 func (a App) Handler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPost && r.Header.Get("Authorization") != a.secret {
-			httperror.Unauthorized(w, ErrAuthentificationFailed)
-			return
+		// reads are public, credentials give access to private repositories of the tenant
+		if r.Method != http.MethodGet || isAuthenticatedPath(r.URL.Path) || hasCredentials(r) {
+			token, err := a.authenticate(r)
+			if err != nil {
+				if errors.Is(err, ErrAuthentificationFailed) {
+					// guesses of the secret are limited like anonymous requests, allowed writes are audited without actor
+					if a.allow(w, r) {
+						if model.IsWriteMethod(r.Method) {
+							var saveAudit func()
+							w, r, saveAudit = a.startAudit(w, r, model.Token{})
+							defer saveAudit()
+						}
+
+						httperror.Unauthorized(w, err)
+					}
+				} else {
+					httperror.InternalServerError(w, err)
+				}
+				return
+			}
+
+			r = r.WithContext(withToken(r.Context(), token))
+
+			if !a.allow(w, r) {
+				return
+			}
+
+			if model.IsWriteMethod(r.Method) {
+				var saveAudit func()
+				w, r, saveAudit = a.startAudit(w, r, token)
+				defer saveAudit()
+
+				if !a.limitBody(w, r) {
+					return
+				}
+			}
+		} else {
+			if !a.allow(w, r) {
+				return
+			}
+
+			r = a.withSession(r)
 		}
 
 		if strings.HasPrefix(r.URL.Path, commitsPath) {
@@ -88,16 +184,117 @@ func (a App) Handler() http.Handler {
 			return
 		}
 
+		if strings.HasPrefix(r.URL.Path, typesPath) {
+			a.handleTypes(w, r)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, componentsPath) {
+			a.handleComponents(w, r)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, tokensPath) {
+			a.handleTokens(w, r)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, webhooksPath) {
+			a.handleWebhooks(w, r)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, subscriptionsPath) {
+			a.handleSubscriptions(w, r)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, repositoriesPath) {
+			a.handleRepositories(w, r)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, deploymentsPath) {
+			a.handleDeployments(w, r)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, auditPath) {
+			a.handleAudit(w, r)
+			return
+		}
+
+		if r.URL.Path == exportPath {
+			a.handleExport(w, r)
+			return
+		}
+
+		if r.URL.Path == openapiPath {
+			a.handleOpenAPI(w, r)
+			return
+		}
+
 		httperror.NotFound(w)
 	})
 }
 
+func hasCredentials(r *http.Request) bool {
+	return len(r.Header.Get("Authorization")) != 0
+}
+
+// withSession adds the session of the user logged in the web UI to the request, reads are then restricted to its visibility
+func (a App) withSession(r *http.Request) *http.Request {
+	if session, ok := a.sessions.Session(r); ok {
+		return r.WithContext(oidc.WithSession(r.Context(), session))
+	}
+
+	return r
+}
+
+func isAuthenticatedPath(urlPath string) bool {
+	return strings.HasPrefix(urlPath, tokensPath) || strings.HasPrefix(urlPath, repositoriesPath) || strings.HasPrefix(urlPath, webhooksPath) || strings.HasPrefix(urlPath, subscriptionsPath) || strings.HasPrefix(urlPath, auditPath)
+}
+
 func (a App) TemplateFunc(w http.ResponseWriter, r *http.Request) (renderer.Page, error) {
 	if strings.HasPrefix(r.URL.Path, apiPath) {
 		a.apiHandler.ServeHTTP(w, r)
 		return renderer.Page{}, nil
 	}
 
+	if a.sessions.Enabled() && strings.HasPrefix(r.URL.Path, oidc.Path) {
+		a.sessions.Handler().ServeHTTP(w, r)
+		return renderer.Page{}, nil
+	}
+
+	if hasCredentials(r) {
+		token, err := a.authenticate(r)
+		if err != nil {
+			if errors.Is(err, ErrAuthentificationFailed) {
+				if !a.allow(w, r) {
+					return renderer.Page{}, nil
+				}
+
+				return renderer.NewPage("", http.StatusUnauthorized, nil), err
+			}
+
+			return renderer.NewPage("", http.StatusInternalServerError, nil), err
+		}
+
+		r = r.WithContext(withToken(r.Context(), token))
+	} else {
+		r = a.withSession(r)
+	}
+
+	if !a.allow(w, r) {
+		return renderer.Page{}, nil
+	}
+
+	now := time.Now()
+	if a.isPageNotModified(w, r, now) {
+		w.WriteHeader(http.StatusNotModified)
+		return renderer.Page{}, nil
+	}
+
 	commits, _, err := a.listCommits(r)
 	if err != nil {
 		return renderer.NewPage("", http.StatusInternalServerError, nil), err
@@ -108,20 +305,32 @@ func (a App) TemplateFunc(w http.ResponseWriter, r *http.Request) (renderer.Page
 		return renderer.NewPage("", http.StatusInternalServerError, nil), fmt.Errorf("parse query: %w", err)
 	}
 
-	filters, err := a.storeApp.ListFilters(r.Context())
+	filters, err := a.storeApp.ListFilters(r.Context(), readerVisibility(r.Context()))
 	if err != nil {
 		return renderer.NewPage("", http.StatusInternalServerError, nil), fmt.Errorf("list filters: %w", err)
 	}
 
+	deployments, err := a.storeApp.ListCurrentDeployments(r.Context(), params["repository"])
+	if err != nil {
+		return renderer.NewPage("", http.StatusInternalServerError, nil), fmt.Errorf("list deployments: %w", err)
+	}
+
+	session, _ := oidc.FromContext(r.Context())
+
 	return renderer.NewPage("public", http.StatusOK, map[string]any{
 		"Path":         r.URL.Path,
 		"Filters":      params,
 		"Repositories": filters["repository"],
 		"Types":        filters["type"],
 		"Components":   filters["component"],
+		"Tenants":      filters["tenant"],
 		"Colors":       repositoriesColors,
+		"TypeColors":   a.vocabulary.Types(),
 		"Commits":      commits.Commits,
-		"Now":          time.Now(),
+		"Deployments":  deploymentsByCommit(deployments),
+		"Now":          now,
+		"Login":        a.sessions.Enabled(),
+		"Session":      session,
 	}), nil
 }
 
@@ -136,40 +345,80 @@ func (a App) listCommits(r *http.Request) (model.CommitsList, query.Pagination,
 		return model.CommitsList{}, pagination, httpModel.WrapInvalid(err)
 	}
 
-	params := r.URL.Query()
+	search, err := a.parseSearch(ctx, r.URL.Query())
+	if err != nil {
+		return model.CommitsList{}, pagination, err
+	}
+
+	commits, err := a.storeApp.SearchCommit(ctx, search.query, search.filters, search.before, search.after, pagination.PageSize, pagination.Last)
+	commits.Commits = a.vocabulary.LinkReferences(commits.Commits)
+
+	return commits, pagination, err
+}
 
-	searchQuery := strings.TrimSpace(params.Get("q"))
-	filters := map[string][]string{
-		"repository": params["repository"],
-		"type":       params["type"],
-		"component":  params["component"],
+type commitSearch struct {
+	filters map[string][]string
+	query   string
+	before  string
+	after   string
+}
+
+// parseSearch reads the search of the request, with canonical types and components, restricted to repositories visible by the reader of the context
+func (a App) parseSearch(ctx context.Context, params url.Values) (commitSearch, error) {
+	output := commitSearch{
+		query: strings.TrimSpace(params.Get("q")),
+		filters: map[string][]string{
+			"repository": params["repository"],
+			"type":       a.vocabulary.ResolveTypes(params["type"]),
+			"component":  a.vocabulary.ResolveComponents(params["component"]),
+			"path":       cleanPaths(params["path"]),
+			"ref":        cleanReferences(params["ref"]),
+			"branch":     cleanBranches(params["branch"]),
+			"tenant":     params["tenant"],
+			"visibility": readerVisibility(ctx),
+		},
+		before: strings.TrimSpace(params.Get("before")),
+		after:  strings.TrimSpace(params.Get("after")),
 	}
 
-	before := strings.TrimSpace(params.Get("before"))
-	if err := checkDate(before); err != nil {
-		return model.CommitsList{}, pagination, httpModel.WrapInvalid(err)
+	if err := checkDate(output.before); err != nil {
+		return output, httpModel.WrapInvalid(err)
 	}
 
-	after := strings.TrimSpace(params.Get("after"))
-	if err := checkDate(after); err != nil {
-		return model.CommitsList{}, pagination, httpModel.WrapInvalid(err)
+	if err := checkDate(output.after); err != nil {
+		return output, httpModel.WrapInvalid(err)
 	}
 
-	commits, err := a.storeApp.SearchCommit(ctx, searchQuery, filters, before, after, pagination.PageSize, pagination.Last)
-	return commits, pagination, err
+	return output, nil
 }
 
 func (a App) handleCommits(w http.ResponseWriter, r *http.Request) {
-	if r.Method == http.MethodPost {
+	switch r.Method {
+	case http.MethodPost:
 		a.handlePostCommits(w, r)
-	} else if r.Method == http.MethodGet {
-		a.handleGetCommits(w, r)
-	} else {
+	case http.MethodGet:
+		if r.URL.Path == commitsPath+streamPath {
+			a.handleStream(w, r)
+		} else if r.URL.Path == commitsPath+rangePath {
+			a.handleCommitRange(w, r)
+		} else {
+			a.handleGetCommits(w, r)
+		}
+	case http.MethodPatch:
+		a.handlePatchCommit(w, r)
+	case http.MethodDelete:
+		a.handleDeleteCommits(w, r)
+	default:
 		w.WriteHeader(http.StatusMethodNotAllowed)
 	}
 }
 
 func (a App) handleGetCommits(w http.ResponseWriter, r *http.Request) {
+	if a.isNotModified(w, r) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	commits, pagination, err := a.listCommits(r)
 	if err != nil {
 		if errors.Is(err, httpModel.ErrInvalid) {
@@ -192,24 +441,96 @@ func (a App) handleGetCommits(w http.ResponseWriter, r *http.Request) {
 func (a App) handlePostCommits(w http.ResponseWriter, r *http.Request) {
 	var commit model.Commit
 	if err := httpjson.Parse(r, &commit); err != nil {
-		httperror.BadRequest(w, err)
+		writeParseError(w, err)
 		return
 	}
 
 	commit = commit.Sanitize()
-	if err := commit.Check(); err != nil {
+	if len(commit.PickedFrom) == 0 {
+		commit.Content, commit.PickedFrom = a.vocabulary.ExtractCherryPick(commit.Content)
+	}
+
+	if err := commit.Check(a.maxContentLength); err != nil {
 		httperror.BadRequest(w, err)
 		return
 	}
 
-	if err := a.storeApp.SaveCommit(r.Context(), commit); err != nil {
-		httperror.InternalServerError(w, fmt.Errorf("save commit for `%s` with hash `%s`: %w", commit.Repository, commit.Hash, err))
+	var err error
+	if commit.Type, err = a.vocabulary.ResolveType(commit.Type); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	if len(commit.Component) == 0 {
+		commit.Component = a.vocabulary.InferComponent(commit.Repository, commit.Paths)
+	}
+
+	commit.Component = a.vocabulary.ResolveComponent(commit.Component)
+	commit.References = a.vocabulary.ExtractReferences(commit.Content)
+
+	auditTarget(r.Context(), commit.Repository, commit.Hash)
+
+	if err = a.checkRepositoryAccess(r.Context(), commit.Repository); err != nil {
+		httperror.HandleError(w, err)
+		return
+	}
+
+	if commit.Tenant, err = commitTenant(r.Context(), commit.Tenant); err != nil {
+		httperror.HandleError(w, err)
+		return
+	}
+
+	created, err := a.storeApp.SaveCommit(r.Context(), commit)
+	if err != nil {
+		httperror.HandleError(w, fmt.Errorf("save commit for `%s` with hash `%s`: %w", commit.Repository, commit.Hash, err))
+		return
+	}
+
+	// sending a commit again is not an error, so scripts can resend a range
+	if !created {
+		w.WriteHeader(http.StatusOK)
 		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
 }
 
+func cleanPaths(paths []string) []string {
+	output := make([]string, 0, len(paths))
+
+	for _, item := range paths {
+		if item = model.CleanPath(item); len(item) != 0 {
+			output = append(output, item)
+		}
+	}
+
+	return output
+}
+
+func cleanReferences(references []string) []string {
+	output := make([]string, 0, len(references))
+
+	for _, item := range references {
+		if item = strings.ToUpper(strings.TrimSpace(item)); len(item) != 0 {
+			output = append(output, item)
+		}
+	}
+
+	return output
+}
+
+func cleanBranches(branches []string) []string {
+	output := make([]string, 0, len(branches))
+
+	for _, item := range branches {
+		if item = strings.TrimSpace(item); len(item) != 0 {
+			output = append(output, item)
+		}
+	}
+
+	return output
+}
+
 func checkDate(raw string) error {
 	if len(raw) == 0 {
 		return nil
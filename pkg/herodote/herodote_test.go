@@ -1,14 +1,21 @@
 package herodote
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
 	"github.com/ViBiOh/httputils/v4/pkg/request"
 )
 
@@ -17,7 +24,7 @@ func TestFlags(t *testing.T) {
 		want string
 	}{
 		"simple": {
-			"Usage of simple:\n  -httpSecret string\n    \t[herodote] HTTP Secret Key for Update ${SIMPLE_HTTP_SECRET}\n",
+			"Usage of simple:\n  -httpSecret string\n    \t[herodote] HTTP Secret Key for Update ${SIMPLE_HTTP_SECRET}\n  -maxBodySize int\n    \t[herodote] Maximum size of a request body, in bytes ${SIMPLE_MAX_BODY_SIZE} (default 1048576)\n  -maxContentLength uint\n    \t[herodote] Maximum number of characters of a commit's content, 0 to disable ${SIMPLE_MAX_CONTENT_LENGTH} (default 10000)\n  -trustedProxies uint\n    \t[herodote] Reverse proxies in front of the API appending to X-Forwarded-For, the client IP is the connection's one if 0 ${SIMPLE_TRUSTED_PROXIES}\n",
 		},
 	}
 
@@ -59,20 +66,27 @@ func TestHandler(t *testing.T) {
 			http.Header{},
 		},
 		"post invalid token": {
-			App{secret: "testing"},
+			App{storeApp: auditStore{}, secret: "testing"},
 			httptest.NewRequest(http.MethodPost, "/", nil),
 			fmt.Sprintf("%s\n", ErrAuthentificationFailed.Error()),
 			http.StatusUnauthorized,
 			http.Header{},
 		},
 		"post valid": {
-			App{secret: "testing"},
+			App{storeApp: auditStore{}, secret: "testing"},
 			postWithToken,
 			`¯\_(ツ)_/¯
 `,
 			http.StatusNotFound,
 			http.Header{},
 		},
+		"tokens without token": {
+			App{secret: "testing"},
+			httptest.NewRequest(http.MethodGet, "/tokens", nil),
+			fmt.Sprintf("%s\n", ErrAuthentificationFailed.Error()),
+			http.StatusUnauthorized,
+			http.Header{},
+		},
 	}
 
 	for intention, tc := range cases {
@@ -98,6 +112,64 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+// savedStore creates a commit only once by hash
+type savedStore struct {
+	Store
+	saved map[string]bool
+}
+
+func (s savedStore) SaveCommit(_ context.Context, commit model.Commit) (bool, error) {
+	created := !s.saved[commit.Hash]
+	s.saved[commit.Hash] = true
+
+	return created, nil
+}
+
+func TestPostCommits(t *testing.T) {
+	fs := flag.NewFlagSet("post", flag.ContinueOnError)
+	vocabularyApp, err := vocabulary.New(vocabulary.Flags(fs, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := `{"hash":"1a2bc34d","type":"feat","content":"Add README.md","date":"2023-01-01T00:00:00Z","remote":"github.com","repository":"vibioh/herodote"`
+
+	cases := map[string]struct {
+		body       string
+		wantFirst  int
+		wantSecond int
+	}{
+		"without branches": {
+			commit + "}",
+			http.StatusCreated,
+			http.StatusOK,
+		},
+		"with branches": {
+			commit + `,"branches":["main"]}`,
+			http.StatusCreated,
+			http.StatusOK,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			instance := App{storeApp: savedStore{saved: make(map[string]bool)}, vocabulary: vocabularyApp}
+
+			for _, want := range []int{tc.wantFirst, tc.wantSecond} {
+				request := httptest.NewRequest(http.MethodPost, "/commits", strings.NewReader(tc.body))
+				request = request.WithContext(withToken(request.Context(), model.AdminToken))
+
+				writer := httptest.NewRecorder()
+				instance.handleCommits(writer, request)
+
+				if writer.Code != want {
+					t.Errorf("handleCommits() = %d, want %d: %s", writer.Code, want, writer.Body.String())
+				}
+			}
+		})
+	}
+}
+
 func TestCheckDate(t *testing.T) {
 	type args struct {
 		raw string
@@ -147,3 +219,55 @@ func TestCheckDate(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSearch(t *testing.T) {
+	componentsFile := filepath.Join(t.TempDir(), "components.json")
+	if err := os.WriteFile(componentsFile, []byte(`[{"name": "api", "aliases": ["apis"]}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	vocabularyConfig := vocabulary.Flags(fs, "")
+	if err := fs.Parse([]string{"-componentsFile", componentsFile}); err != nil {
+		t.Fatal(err)
+	}
+
+	vocabularyApp, err := vocabulary.New(vocabularyConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		params        url.Values
+		wantTypes     []string
+		wantComponent []string
+	}{
+		"canonical": {
+			url.Values{"type": {"feat"}, "component": {"api"}},
+			[]string{"feat"},
+			[]string{"api"},
+		},
+		"aliases": {
+			url.Values{"type": {"Feature", "bugfix"}, "component": {"apis"}},
+			[]string{"feat", "fix"},
+			[]string{"api"},
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			got, gotErr := App{vocabulary: vocabularyApp}.parseSearch(context.Background(), tc.params)
+			if gotErr != nil {
+				t.Fatalf("parseSearch() = `%s`", gotErr)
+			}
+
+			if !reflect.DeepEqual(got.filters["type"], tc.wantTypes) {
+				t.Errorf("parseSearch() types = %v, want %v", got.filters["type"], tc.wantTypes)
+			}
+
+			if !reflect.DeepEqual(got.filters["component"], tc.wantComponent) {
+				t.Errorf("parseSearch() components = %v, want %v", got.filters["component"], tc.wantComponent)
+			}
+		})
+	}
+}
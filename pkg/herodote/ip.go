@@ -0,0 +1,40 @@
+package herodote
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// clientIP returns the address of the connection, or the X-Forwarded-For entry appended by the farthest trusted proxy.
+// Entries on its left are given by the client and can't be trusted.
+func (a App) clientIP(r *http.Request) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+
+	if a.trustedProxies == 0 {
+		return remote
+	}
+
+	var entries []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, entry := range strings.Split(header, ",") {
+			if entry = strings.TrimSpace(entry); len(entry) != 0 {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	if len(entries) == 0 {
+		return remote
+	}
+
+	// each trusted proxy appends the address it received the request from, the nearest one being the connection
+	if uint(len(entries)) < a.trustedProxies {
+		return entries[0]
+	}
+
+	return entries[uint(len(entries))-a.trustedProxies]
+}
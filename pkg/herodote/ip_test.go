@@ -0,0 +1,64 @@
+package herodote
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	cases := map[string]struct {
+		forwarded      []string
+		trustedProxies uint
+		want           string
+	}{
+		"connection": {
+			nil,
+			0,
+			"192.0.2.1",
+		},
+		"untrusted header": {
+			[]string{"10.0.0.1"},
+			0,
+			"192.0.2.1",
+		},
+		"proxy": {
+			[]string{"10.0.0.1"},
+			1,
+			"10.0.0.1",
+		},
+		"spoofed": {
+			[]string{" 10.0.0.1 , 172.16.0.1"},
+			1,
+			"172.16.0.1",
+		},
+		"proxies": {
+			[]string{"10.0.0.1, 172.16.0.1", "172.16.0.2"},
+			2,
+			"172.16.0.1",
+		},
+		"fewer entries": {
+			[]string{"10.0.0.1"},
+			2,
+			"10.0.0.1",
+		},
+		"no header": {
+			nil,
+			1,
+			"192.0.2.1",
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/", nil)
+			for _, forwarded := range tc.forwarded {
+				request.Header.Add("X-Forwarded-For", forwarded)
+			}
+
+			if got := (App{trustedProxies: tc.trustedProxies}).clientIP(request); got != tc.want {
+				t.Errorf("clientIP() = `%s`, want `%s`", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package herodote
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/ratelimit"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+)
+
+// allow consumes a request of the token if authenticated, of the client IP otherwise, and writes the 429 once the limit is reached
+func (a App) allow(w http.ResponseWriter, r *http.Request) bool {
+	var wait time.Duration
+	var ok bool
+
+	if token, authenticated := TokenFromContext(r.Context()); authenticated {
+		wait, ok = a.limiter.Token(r.Context(), token.Name)
+	} else {
+		wait, ok = a.limiter.IP(r.Context(), a.clientIP(r))
+	}
+
+	if !ok {
+		ratelimit.TooManyRequests(w, wait)
+	}
+
+	return ok
+}
+
+// limitBody rejects a body announced larger than the maximum, others are read up to the maximum
+func (a App) limitBody(w http.ResponseWriter, r *http.Request) bool {
+	if a.maxBodySize <= 0 {
+		return true
+	}
+
+	if r.ContentLength > a.maxBodySize {
+		http.Error(w, fmt.Sprintf("request body is larger than %d bytes", a.maxBodySize), http.StatusRequestEntityTooLarge)
+		return false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, a.maxBodySize)
+
+	return true
+}
+
+// writeParseError writes the 413 of a body read up to the maximum, the 400 of an invalid one otherwise
+func writeParseError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		http.Error(w, fmt.Sprintf("request body is larger than %d bytes", maxBytesErr.Limit), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	httperror.BadRequest(w, err)
+}
@@ -0,0 +1,115 @@
+package herodote
+
+import (
+	"context"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/ratelimit"
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+)
+
+// limitStore knows no token
+type limitStore struct {
+	createDeploymentStore
+}
+
+func (limitStore) GetTokenByHash(_ context.Context, _ string) (model.Token, string, error) {
+	return model.Token{}, "", nil
+}
+
+func newTestLimiter(t *testing.T) ratelimit.App {
+	t.Helper()
+
+	fs := flag.NewFlagSet("ratelimit", flag.ContinueOnError)
+	config := ratelimit.Flags(fs, "")
+
+	if err := fs.Parse([]string{"-tokenRate", "1", "-tokenBurst", "1", "-ipRate", "1", "-ipBurst", "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	return ratelimit.New(config, redis.Noop{})
+}
+
+func TestLimits(t *testing.T) {
+	deployment := `{"repository":"vibioh/herodote","environment":"production","hash":"1a2bc34d"}`
+
+	cases := map[string]struct {
+		request    func() *http.Request
+		wantFirst  int
+		wantSecond int
+	}{
+		"anonymous": {
+			func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/unknown", nil)
+			},
+			http.StatusNotFound,
+			http.StatusTooManyRequests,
+		},
+		"token": {
+			func() *http.Request {
+				request := httptest.NewRequest(http.MethodPost, "/deployments", strings.NewReader(deployment))
+				request.Header.Set("Authorization", "secret")
+				return request
+			},
+			http.StatusCreated,
+			http.StatusTooManyRequests,
+		},
+		"invalid secret": {
+			func() *http.Request {
+				request := httptest.NewRequest(http.MethodPost, "/deployments", strings.NewReader(deployment))
+				request.Header.Set("Authorization", "guess")
+				return request
+			},
+			http.StatusUnauthorized,
+			http.StatusTooManyRequests,
+		},
+		"announced body": {
+			func() *http.Request {
+				request := httptest.NewRequest(http.MethodPost, "/deployments", strings.NewReader(deployment+strings.Repeat(" ", 100)))
+				request.Header.Set("Authorization", "secret")
+				return request
+			},
+			http.StatusRequestEntityTooLarge,
+			http.StatusTooManyRequests,
+		},
+		"streamed body": {
+			func() *http.Request {
+				request := httptest.NewRequest(http.MethodPost, "/deployments", io.MultiReader(strings.NewReader(`{"repository":"`), strings.NewReader(strings.Repeat("a", 200)+`"}`)))
+				request.Header.Set("Authorization", "secret")
+				return request
+			},
+			http.StatusRequestEntityTooLarge,
+			http.StatusTooManyRequests,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			instance := App{storeApp: limitStore{}, secret: "secret", limiter: newTestLimiter(t), maxBodySize: 128}
+
+			writer := httptest.NewRecorder()
+			instance.Handler().ServeHTTP(writer, tc.request())
+
+			if writer.Code != tc.wantFirst {
+				t.Errorf("Handler() = %d, want %d: %s", writer.Code, tc.wantFirst, writer.Body.String())
+			}
+
+			writer = httptest.NewRecorder()
+			instance.Handler().ServeHTTP(writer, tc.request())
+
+			if writer.Code != tc.wantSecond {
+				t.Errorf("Handler() = %d, want %d", writer.Code, tc.wantSecond)
+			}
+
+			if got := writer.Header().Get("Retry-After"); got != "60" {
+				t.Errorf("Handler() Retry-After = `%s`, want `60`", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package herodote
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+)
+
+const openapiPath = "/openapi.json"
+
+//go:embed openapi.json
+var openapi []byte
+
+func (a App) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", cacheControl)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(openapi); err != nil {
+		logger.Error("write openapi: %s", err)
+	}
+}
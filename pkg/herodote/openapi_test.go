@@ -0,0 +1,258 @@
+package herodote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
+)
+
+// openapiStore succeeds on every call, for routes to reach their success response
+type openapiStore struct{}
+
+func (openapiStore) Enabled() bool { return true }
+func (openapiStore) ListFilters(context.Context, []string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+func (openapiStore) LastModified(context.Context) (time.Time, error) { return time.Time{}, nil }
+func (openapiStore) SearchCommit(context.Context, string, map[string][]string, string, string, uint, string) (model.CommitsList, error) {
+	return model.CommitsList{}, nil
+}
+func (openapiStore) ExportCommits(context.Context, string, map[string][]string, string, string, func(model.CommitEvent) error) error {
+	return nil
+}
+func (openapiStore) SaveCommit(context.Context, model.Commit) (bool, error) { return true, nil }
+func (openapiStore) GetCommit(_ context.Context, repository, hash string) (model.Commit, error) {
+	return model.Commit{Repository: repository, Hash: hash, Type: "feat", Content: "Add OpenAPI", Remote: "github.com", Date: time.Now()}, nil
+}
+func (openapiStore) FindCommit(_ context.Context, repository, hash string) (model.Commit, error) {
+	return model.Commit{Repository: repository, Hash: hash, Type: "feat", Content: "Add OpenAPI", Remote: "github.com", Date: time.Date(2023, 7, 20, 6, 0, 0, 0, time.UTC)}, nil
+}
+func (openapiStore) UpdateCommit(context.Context, string, string, model.Commit) error { return nil }
+func (openapiStore) DeleteCommit(context.Context, string, string) error               { return nil }
+func (openapiStore) DeleteRepository(context.Context, string) (uint64, error)         { return 0, nil }
+func (openapiStore) RewriteComponent(context.Context, string, string) (uint64, error) { return 0, nil }
+func (openapiStore) ListAliases(context.Context) ([]model.RepositoryAlias, error)     { return nil, nil }
+func (openapiStore) RenameRepository(context.Context, model.RepositoryAlias) (uint64, error) {
+	return 0, nil
+}
+func (openapiStore) SaveAudit(context.Context, model.AuditEntry) error { return nil }
+func (openapiStore) ListAudit(context.Context, model.AuditFilter, uint, uint64) ([]model.AuditEntry, uint, error) {
+	return nil, 0, nil
+}
+func (openapiStore) ListTokens(context.Context) ([]model.Token, error) { return nil, nil }
+func (openapiStore) GetTokenByHash(context.Context, string) (model.Token, string, error) {
+	return model.Token{}, "", nil
+}
+func (openapiStore) CreateToken(context.Context, model.Token, string) error { return nil }
+func (openapiStore) DeleteToken(context.Context, string) error              { return nil }
+func (openapiStore) TouchToken(context.Context, string) error               { return nil }
+func (openapiStore) ListWebhooks(context.Context) ([]model.Webhook, error)  { return nil, nil }
+func (openapiStore) CreateWebhook(context.Context, model.Webhook) error     { return nil }
+func (openapiStore) DeleteWebhook(context.Context, string) error            { return nil }
+func (openapiStore) ListWebhookFailures(context.Context, string, uint) ([]model.WebhookFailure, error) {
+	return nil, nil
+}
+func (openapiStore) ListSubscriptions(context.Context, string) ([]model.Subscription, error) {
+	return nil, nil
+}
+func (openapiStore) CreateSubscription(context.Context, model.Subscription) (uint64, error) {
+	return 1, nil
+}
+func (openapiStore) DeleteSubscription(context.Context, uint64, string) error { return nil }
+func (openapiStore) StreamCommits() (<-chan model.CommitEvent, func()) {
+	events := make(chan model.CommitEvent)
+	close(events)
+
+	return events, func() {}
+}
+func (openapiStore) ListCommitEvents(context.Context, uint64, uint) ([]model.CommitEvent, error) {
+	return nil, nil
+}
+func (openapiStore) CreateDeployment(context.Context, model.Deployment) (uint64, error) {
+	return 1, nil
+}
+func (openapiStore) ListDeployments(_ context.Context, repository, environment, status string, _ []string, _ uint) ([]model.Deployment, error) {
+	return []model.Deployment{{Repository: repository, Environment: environment, Hash: "1a2bc34d", Status: status, Date: time.Now()}}, nil
+}
+func (openapiStore) ListCurrentDeployments(context.Context, []string) ([]model.Deployment, error) {
+	return nil, nil
+}
+func (openapiStore) ListPrivateRepositories(context.Context) ([]model.PrivateRepository, error) {
+	return nil, nil
+}
+func (openapiStore) PrivateRepositories(context.Context) (model.PrivateRepositories, error) {
+	return nil, nil
+}
+func (openapiStore) SetPrivateRepository(context.Context, model.PrivateRepository) error { return nil }
+func (openapiStore) DeletePrivateRepository(context.Context, string) error               { return nil }
+
+type openapiParameter struct {
+	Example  any    `json:"example"`
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+}
+
+type openapiOperation struct {
+	RequestBody *struct {
+		Content map[string]struct {
+			Example json.RawMessage `json:"example"`
+		} `json:"content"`
+	} `json:"requestBody"`
+	Responses  map[string]json.RawMessage `json:"responses"`
+	Parameters []openapiParameter         `json:"parameters"`
+}
+
+type openapiDocument struct {
+	Paths      map[string]map[string]openapiOperation `json:"paths"`
+	Components map[string]map[string]json.RawMessage  `json:"components"`
+	OpenAPI    string                                 `json:"openapi"`
+}
+
+func TestOpenAPI(t *testing.T) {
+	var document openapiDocument
+	if err := json.Unmarshal(openapi, &document); err != nil {
+		t.Fatalf("unmarshal openapi: %s", err)
+	}
+
+	if !strings.HasPrefix(document.OpenAPI, "3.") {
+		t.Errorf("openapi = `%s`, want 3.x", document.OpenAPI)
+	}
+
+	checkReferences(t, openapi, document)
+
+	// every route of Handler must be documented
+	for _, route := range []string{commitsPath, commitsPath + streamPath, commitsPath + rangePath, typesPath, componentsPath, componentsPath + rewritePath, tokensPath, webhooksPath, webhooksPath + "/{name}" + failuresPath, subscriptionsPath, repositoriesPath + aliasesPath, repositoriesPath + privatePath, deploymentsPath, deploymentsPath + comparePath, deploymentsPath + changesPath, exportPath, auditPath, openapiPath} {
+		if _, ok := document.Paths[route]; !ok {
+			t.Errorf("route `%s` is not documented", route)
+		}
+	}
+
+	fs := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	vocabularyConfig := vocabulary.Flags(fs, "")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	vocabularyApp, err := vocabulary.New(vocabularyConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance := App{secret: "testing", storeApp: openapiStore{}, vocabulary: vocabularyApp}
+	handler := instance.Handler()
+
+	// every documented operation must be served with a documented success
+	for urlPath, operations := range document.Paths {
+		for method, operation := range operations {
+			t.Run(strings.ToUpper(method)+" "+urlPath, func(t *testing.T) {
+				request := operationRequest(t, strings.ToUpper(method), urlPath, operation)
+				request.Header.Set("Authorization", "testing")
+
+				writer := httptest.NewRecorder()
+				handler.ServeHTTP(writer, request)
+
+				if writer.Code >= http.StatusMultipleChoices {
+					t.Fatalf("%s %s = %d `%s`, want a success", request.Method, request.URL, writer.Code, strings.TrimSpace(writer.Body.String()))
+				}
+
+				if _, ok := operation.Responses[strconv.Itoa(writer.Code)]; !ok {
+					t.Errorf("%s %s = %d, not documented in %v", request.Method, request.URL, writer.Code, sortedResponses(operation))
+				}
+			})
+		}
+	}
+}
+
+func operationRequest(t *testing.T, method, urlPath string, operation openapiOperation) *http.Request {
+	t.Helper()
+
+	query := make([]string, 0)
+
+	for _, parameter := range operation.Parameters {
+		switch {
+		case parameter.In == "path":
+			urlPath = strings.ReplaceAll(urlPath, "{"+parameter.Name+"}", fmt.Sprint(parameter.Example))
+		case parameter.In == "query" && parameter.Required:
+			query = append(query, fmt.Sprintf("%s=%v", parameter.Name, parameter.Example))
+		}
+	}
+
+	if strings.Contains(urlPath, "{") {
+		t.Fatalf("path `%s` has a parameter without example", urlPath)
+	}
+
+	if len(query) != 0 {
+		urlPath += "?" + strings.Join(query, "&")
+	}
+
+	var body io.Reader
+	if operation.RequestBody != nil {
+		content, ok := operation.RequestBody.Content["application/json"]
+		if !ok || len(content.Example) == 0 {
+			t.Fatalf("request body of `%s %s` has no JSON example", method, urlPath)
+		}
+
+		body = bytes.NewReader(content.Example)
+	}
+
+	return httptest.NewRequest(method, urlPath, body)
+}
+
+// checkReferences ensures every `$ref` points to a component
+func checkReferences(t *testing.T, content []byte, document openapiDocument) {
+	t.Helper()
+
+	var raw any
+	if err := json.Unmarshal(content, &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	var walk func(any)
+	walk = func(value any) {
+		switch typed := value.(type) {
+		case map[string]any:
+			for key, item := range typed {
+				if key != "$ref" {
+					walk(item)
+					continue
+				}
+
+				parts := strings.Split(strings.TrimPrefix(fmt.Sprint(item), "#/components/"), "/")
+				if len(parts) != 2 || document.Components[parts[0]][parts[1]] == nil {
+					t.Errorf("reference `%s` is not found", item)
+				}
+			}
+		case []any:
+			for _, item := range typed {
+				walk(item)
+			}
+		}
+	}
+
+	walk(raw)
+}
+
+func sortedResponses(operation openapiOperation) []string {
+	output := make([]string, 0, len(operation.Responses))
+	for status := range operation.Responses {
+		output = append(output, status)
+	}
+
+	sort.Strings(output)
+
+	return output
+}
@@ -0,0 +1,99 @@
+package herodote
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+)
+
+const (
+	rangePath = "/range"
+
+	// deploymentRefPrefix designates the last successful deployment of an environment as a bound
+	deploymentRefPrefix = "deployment:"
+
+	// rangeMaxSize caps the commits of a range, the total count is still given
+	rangeMaxSize = 1000
+)
+
+// handleCommitRange writes the changelog of commits after `from`, up to and including `to`
+func (a App) handleCommitRange(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	repository := cleanParam(params.Get("repository"))
+	fromRef := strings.TrimSpace(params.Get("from"))
+	toRef := strings.TrimSpace(params.Get("to"))
+
+	if len(repository) == 0 || len(fromRef) == 0 || len(toRef) == 0 {
+		httperror.BadRequest(w, errors.New("repository, from and to are required"))
+		return
+	}
+
+	search, err := a.parseSearch(r.Context(), params)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	if err = a.checkRepositoryVisibility(r.Context(), repository); err != nil {
+		httperror.HandleError(w, err)
+		return
+	}
+
+	from, err := a.resolveBound(r, repository, fromRef)
+	if err != nil {
+		httperror.HandleError(w, err)
+		return
+	}
+
+	to, err := a.resolveBound(r, repository, toRef)
+	if err != nil {
+		httperror.HandleError(w, err)
+		return
+	}
+
+	if from.Date.After(to.Date) {
+		httperror.BadRequest(w, fmt.Errorf("`%s` is more recent than `%s`", fromRef, toRef))
+		return
+	}
+
+	search.filters["repository"] = []string{repository}
+
+	// dates of commits are stored to the second, the `to` commit is included
+	commits, err := a.storeApp.SearchCommit(r.Context(), search.query, search.filters, searchBound(to.Date.Add(time.Second)), searchBound(from.Date), rangeMaxSize, "")
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	httpjson.Write(w, http.StatusOK, model.CommitRange{
+		From:       from,
+		To:         to,
+		Sections:   a.vocabulary.Changelog(a.vocabulary.LinkReferences(commits.Commits)),
+		TotalCount: commits.TotalCount,
+	})
+}
+
+// resolveBound finds the date of a hash, or of the commit of a deployment when prefixed by `deployment:`
+func (a App) resolveBound(r *http.Request, repository, ref string) (model.RangeBound, error) {
+	if environment, ok := strings.CutPrefix(ref, deploymentRefPrefix); ok {
+		deployments, err := a.lastDeployments(r, repository, cleanParam(environment), 1)
+		if err != nil {
+			return model.RangeBound{}, err
+		}
+
+		return model.RangeBound{Ref: ref, Hash: deployments[0].Commit, Date: deployments[0].CommitDate}, nil
+	}
+
+	commit, err := a.storeApp.FindCommit(r.Context(), repository, strings.ToLower(ref))
+	if err != nil {
+		return model.RangeBound{}, err
+	}
+
+	return model.RangeBound{Ref: ref, Hash: commit.Hash, Date: commit.Date}, nil
+}
@@ -0,0 +1,167 @@
+package herodote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
+	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
+)
+
+type rangeStore struct {
+	deploymentStore
+	commits []model.Commit
+}
+
+func (s rangeStore) FindCommit(_ context.Context, repository, hash string) (model.Commit, error) {
+	var found []model.Commit
+
+	for _, commit := range s.commits {
+		if commit.Repository == repository && (strings.HasPrefix(commit.Hash, hash) || strings.HasPrefix(hash, commit.Hash)) {
+			found = append(found, commit)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return model.Commit{}, httpModel.WrapNotFound(errors.New("not found"))
+	case 1:
+		return found[0], nil
+	default:
+		return model.Commit{}, httpModel.WrapInvalid(errors.New("ambiguous"))
+	}
+}
+
+func (s rangeStore) PrivateRepositories(_ context.Context) (model.PrivateRepositories, error) {
+	return model.PrivateRepositories{"acme/billing": "acme"}, nil
+}
+
+func (s rangeStore) SearchCommit(_ context.Context, _ string, _ map[string][]string, before, after string, _ uint, _ string) (model.CommitsList, error) {
+	*s.bounds = [2]string{before, after}
+
+	return model.CommitsList{Commits: s.commits, TotalCount: uint(len(s.commits))}, nil
+}
+
+func TestHandleCommitRange(t *testing.T) {
+	fs := flag.NewFlagSet("range", flag.ContinueOnError)
+	vocabularyConfig := vocabulary.Flags(fs, "")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	vocabularyApp, err := vocabulary.New(vocabularyConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commits := []model.Commit{
+		{Repository: "vibioh/herodote", Hash: "c3d4e5f6", Type: "fix", Date: time.Date(2023, 7, 22, 6, 0, 0, 0, time.UTC)},
+		{Repository: "vibioh/herodote", Hash: "b2c3d4e5", Type: "feat", Date: time.Date(2023, 7, 21, 6, 0, 0, 0, time.UTC)},
+		{Repository: "vibioh/herodote", Hash: "b2a1f0e9", Type: "feat", Date: time.Date(2023, 7, 20, 6, 0, 0, 0, time.UTC)},
+	}
+
+	deployments := []model.Deployment{
+		{Repository: "vibioh/herodote", Environment: "production", Hash: "b2c3d4e5", Commit: "b2c3d4e5", CommitDate: time.Date(2023, 7, 21, 6, 0, 0, 0, time.UTC)},
+	}
+
+	cases := map[string]struct {
+		url          string
+		wantStatus   int
+		wantBounds   [2]string
+		wantSections []string
+	}{
+		"hashes": {
+			"/commits/range?repository=vibioh/herodote&from=B2A1&to=c3d4e5f6a7b8",
+			http.StatusOK,
+			[2]string{"2023-07-22T06:00:01Z", "2023-07-20T06:00:00Z"},
+			[]string{"Features", "Bug fixes"},
+		},
+		"deployment": {
+			"/commits/range?repository=vibioh/herodote&from=deployment:production&to=c3d4",
+			http.StatusOK,
+			[2]string{"2023-07-22T06:00:01Z", "2023-07-21T06:00:00Z"},
+			[]string{"Features", "Bug fixes"},
+		},
+		"reversed": {
+			"/commits/range?repository=vibioh/herodote&from=c3d4&to=b2a1",
+			http.StatusBadRequest,
+			[2]string{},
+			nil,
+		},
+		"ambiguous": {
+			"/commits/range?repository=vibioh/herodote&from=b2&to=c3d4",
+			http.StatusBadRequest,
+			[2]string{},
+			nil,
+		},
+		"unknown hash": {
+			"/commits/range?repository=vibioh/herodote&from=a0&to=c3d4",
+			http.StatusNotFound,
+			[2]string{},
+			nil,
+		},
+		"never deployed": {
+			"/commits/range?repository=vibioh/herodote&from=deployment:staging&to=c3d4",
+			http.StatusNotFound,
+			[2]string{},
+			nil,
+		},
+		"private repository": {
+			"/commits/range?repository=acme/billing&from=b2a1&to=c3d4",
+			http.StatusNotFound,
+			[2]string{},
+			nil,
+		},
+		"missing bound": {
+			"/commits/range?repository=vibioh/herodote&from=b2a1",
+			http.StatusBadRequest,
+			[2]string{},
+			nil,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			var bounds [2]string
+			store := rangeStore{deploymentStore: deploymentStore{bounds: &bounds, deployments: deployments}, commits: commits}
+			instance := App{storeApp: store, vocabulary: vocabularyApp}
+
+			writer := httptest.NewRecorder()
+			instance.Handler().ServeHTTP(writer, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if writer.Code != tc.wantStatus {
+				t.Fatalf("handleCommitRange() = %d, want %d: %s", writer.Code, tc.wantStatus, writer.Body.String())
+			}
+
+			if bounds != tc.wantBounds {
+				t.Errorf("handleCommitRange() bounds = %v, want %v", bounds, tc.wantBounds)
+			}
+
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got model.CommitRange
+			if err := json.Unmarshal(writer.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+
+			var gotSections []string
+			for _, section := range got.Sections {
+				gotSections = append(gotSections, section.Title)
+			}
+
+			if strings.Join(gotSections, ",") != strings.Join(tc.wantSections, ",") {
+				t.Errorf("handleCommitRange() sections = %v, want %v", gotSections, tc.wantSections)
+			}
+		})
+	}
+}
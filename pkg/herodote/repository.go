@@ -0,0 +1,129 @@
+package herodote
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+)
+
+const (
+	repositoriesPath = "/repositories"
+	aliasesPath      = "/aliases"
+	privatePath      = "/private"
+)
+
+func (a App) handleRepositories(w http.ResponseWriter, r *http.Request) {
+	if token, _ := TokenFromContext(r.Context()); !token.Admin {
+		httperror.Forbidden(w)
+		return
+	}
+
+	subPath := strings.Trim(strings.TrimPrefix(r.URL.Path, repositoriesPath), "/")
+
+	if subPath == strings.Trim(aliasesPath, "/") {
+		switch r.Method {
+		case http.MethodGet:
+			a.handleListAliases(w, r)
+		case http.MethodPost:
+			a.handleRenameRepository(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+
+		return
+	}
+
+	if repository, ok := strings.CutPrefix(subPath, strings.Trim(privatePath, "/")); ok && (len(repository) == 0 || strings.HasPrefix(repository, "/")) {
+		a.handlePrivateRepositories(w, r, strings.Trim(repository, "/"))
+		return
+	}
+
+	httperror.NotFound(w)
+}
+
+func (a App) handlePrivateRepositories(w http.ResponseWriter, r *http.Request, repository string) {
+	switch {
+	case r.Method == http.MethodGet && len(repository) == 0:
+		privates, err := a.storeApp.ListPrivateRepositories(r.Context())
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+
+		httpjson.WriteArray(w, http.StatusOK, privates)
+	case r.Method == http.MethodPost && len(repository) == 0:
+		a.handleSetPrivateRepository(w, r)
+	case r.Method == http.MethodDelete && len(repository) != 0:
+		auditTarget(r.Context(), strings.ToLower(repository), "")
+
+		if err := a.storeApp.DeletePrivateRepository(r.Context(), strings.ToLower(repository)); !httperror.HandleError(w, err) {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (a App) handleSetPrivateRepository(w http.ResponseWriter, r *http.Request) {
+	var private model.PrivateRepository
+	if err := httpjson.Parse(r, &private); err != nil {
+		writeParseError(w, err)
+		return
+	}
+
+	private = private.Sanitize()
+	if err := private.Check(); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	auditTarget(r.Context(), private.Repository, "")
+
+	if err := a.storeApp.SetPrivateRepository(r.Context(), private); err != nil {
+		httperror.InternalServerError(w, fmt.Errorf("set `%s` private to `%s`: %w", private.Repository, private.Tenant, err))
+		return
+	}
+
+	httpjson.Write(w, http.StatusOK, private)
+}
+
+func (a App) handleListAliases(w http.ResponseWriter, r *http.Request) {
+	aliases, err := a.storeApp.ListAliases(r.Context())
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	httpjson.WriteArray(w, http.StatusOK, aliases)
+}
+
+func (a App) handleRenameRepository(w http.ResponseWriter, r *http.Request) {
+	var alias model.RepositoryAlias
+	if err := httpjson.Parse(r, &alias); err != nil {
+		writeParseError(w, err)
+		return
+	}
+
+	alias = alias.Sanitize()
+	if err := alias.Check(); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	auditTarget(r.Context(), alias.Repository, "")
+
+	count, err := a.storeApp.RenameRepository(r.Context(), alias)
+	if err != nil {
+		httperror.InternalServerError(w, fmt.Errorf("rename `%s` to `%s`: %w", alias.Alias, alias.Repository, err))
+		return
+	}
+
+	httpjson.Write(w, http.StatusOK, model.RenamedRepository{
+		RepositoryAlias: alias,
+		Count:           count,
+	})
+}
@@ -0,0 +1,221 @@
+package herodote
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+)
+
+const (
+	streamPath = "/stream"
+
+	lastEventIDHeader = "Last-Event-ID"
+	// streamReplaySize bounds the commits replayed on reconnection, older ones are only available with the search
+	streamReplaySize = 500
+	streamHeartbeat  = time.Second * 30
+)
+
+// handleStream sends Server-Sent Events of saved commits matching the filters of the request
+func (a App) handleStream(w http.ResponseWriter, r *http.Request) {
+	filter, err := a.streamFilter(r.URL.Query())
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	lastID, err := lastEventID(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	// visibility of repositories is read once, changes apply to the next connection
+	privates, err := a.storeApp.PrivateRepositories(r.Context())
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	visibility := readerVisibility(r.Context())
+
+	// subscribing before the replay doesn't miss commits saved meanwhile, duplicates are skipped with their id
+	events, unsubscribe := a.storeApp.StreamCommits()
+	defer unsubscribe()
+
+	var replay []model.CommitEvent
+	if lastID != 0 {
+		if replay, err = a.storeApp.ListCommitEvents(r.Context(), lastID, streamReplaySize); err != nil {
+			httperror.InternalServerError(w, fmt.Errorf("list commits to replay: %w", err))
+			return
+		}
+	}
+
+	controller := http.NewResponseController(w)
+	// a stream outlives the write timeout of the server
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	sent := newRecentIDs(streamReplaySize)
+
+	send := func(event model.CommitEvent) error {
+		if !sent.add(event.ID) {
+			return nil
+		}
+
+		if !filter.Matches(event.Commit) || !privates.Visible(visibility, event.Commit.Repository) {
+			return nil
+		}
+
+		if err := writeEvent(w, event.ID, a.vocabulary.LinkReference(event.Commit)); err != nil {
+			return err
+		}
+
+		return controller.Flush()
+	}
+
+	for _, event := range replay {
+		if err = send(event); err != nil {
+			logger.Warn("send commit event: %s", err)
+			return
+		}
+	}
+
+	if err = controller.Flush(); err != nil {
+		logger.Warn("flush stream: %s", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-a.done:
+			return
+
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if err = send(event); err != nil {
+				logger.Warn("send commit event: %s", err)
+				return
+			}
+
+		case <-heartbeat.C:
+			if _, err = io.WriteString(w, ": heartbeat\n\n"); err == nil {
+				err = controller.Flush()
+			}
+
+			if err != nil {
+				logger.Warn("send heartbeat: %s", err)
+				return
+			}
+		}
+	}
+}
+
+// streamFilter returns the filter of the stream with the vocabulary of the search
+func (a App) streamFilter(params url.Values) (model.CommitFilter, error) {
+	filter := model.CommitFilter{
+		Repositories: params["repository"],
+		Types:        a.vocabulary.ResolveTypes(params["type"]),
+		Components:   a.vocabulary.ResolveComponents(params["component"]),
+		Paths:        params["path"],
+		Refs:         params["ref"],
+	}
+
+	if rawBreaking := strings.TrimSpace(params.Get("breaking")); len(rawBreaking) != 0 {
+		breaking, err := strconv.ParseBool(rawBreaking)
+		if err != nil {
+			return filter, fmt.Errorf("parse breaking: %w", err)
+		}
+
+		filter.Breaking = breaking
+	}
+
+	filter = filter.Sanitize()
+
+	return filter, filter.Check()
+}
+
+// lastEventID returns the id of the last event received, from the header of the reconnection or from the query
+func lastEventID(r *http.Request) (uint64, error) {
+	raw := strings.TrimSpace(r.Header.Get(lastEventIDHeader))
+	if len(raw) == 0 {
+		raw = strings.TrimSpace(r.URL.Query().Get("lastEventId"))
+	}
+
+	if len(raw) == 0 {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse last event id: %w", err)
+	}
+
+	return id, nil
+}
+
+// recentIDs remembers the last ids sent. Ids are given on insert but commits are announced on commit of their transaction,
+// so a lower id may come after a higher one and can't be skipped with the highest id sent.
+type recentIDs struct {
+	ids   map[uint64]struct{}
+	ring  []uint64
+	index int
+}
+
+func newRecentIDs(size int) *recentIDs {
+	return &recentIDs{
+		ids:  make(map[uint64]struct{}, size),
+		ring: make([]uint64, 0, size),
+	}
+}
+
+// add returns false if the id was already sent, the oldest id is forgotten when full
+func (r *recentIDs) add(id uint64) bool {
+	if _, ok := r.ids[id]; ok {
+		return false
+	}
+
+	if len(r.ring) < cap(r.ring) {
+		r.ring = append(r.ring, id)
+	} else {
+		delete(r.ids, r.ring[r.index])
+		r.ring[r.index] = id
+		r.index = (r.index + 1) % len(r.ring)
+	}
+
+	r.ids[id] = struct{}{}
+
+	return true
+}
+
+func writeEvent(w io.Writer, id uint64, commit model.Commit) error {
+	payload, err := json.Marshal(commit)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: commit\ndata: %s\n\n", id, payload)
+
+	return err
+}
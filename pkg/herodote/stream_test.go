@@ -0,0 +1,173 @@
+package herodote
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
+)
+
+type streamStore struct {
+	Store
+	privates model.PrivateRepositories
+	replay   []model.CommitEvent
+	live     []model.CommitEvent
+}
+
+// StreamCommits returns the live events then closes the stream, like a slow subscriber
+func (s streamStore) StreamCommits() (<-chan model.CommitEvent, func()) {
+	events := make(chan model.CommitEvent, len(s.live))
+	for _, event := range s.live {
+		events <- event
+	}
+
+	close(events)
+
+	return events, func() {}
+}
+
+func (s streamStore) PrivateRepositories(_ context.Context) (model.PrivateRepositories, error) {
+	return s.privates, nil
+}
+
+func (s streamStore) ListCommitEvents(_ context.Context, after uint64, _ uint) ([]model.CommitEvent, error) {
+	var output []model.CommitEvent
+
+	for _, event := range s.replay {
+		if event.ID > after {
+			output = append(output, event)
+		}
+	}
+
+	return output, nil
+}
+
+func TestHandleStream(t *testing.T) {
+	fs := flag.NewFlagSet("stream", flag.ContinueOnError)
+	vocabularyConfig := vocabulary.Flags(fs, "")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	vocabularyApp, err := vocabulary.New(vocabularyConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := streamStore{
+		replay: []model.CommitEvent{
+			{ID: 1, Commit: model.Commit{Repository: "vibioh/herodote", Hash: "a1", Type: "feat"}},
+			{ID: 2, Commit: model.Commit{Repository: "vibioh/ketchup", Hash: "b2", Type: "fix"}},
+			{ID: 3, Commit: model.Commit{Repository: "vibioh/herodote", Hash: "c3", Type: "fix"}},
+		},
+		live: []model.CommitEvent{
+			{ID: 3, Commit: model.Commit{Repository: "vibioh/herodote", Hash: "c3", Type: "fix"}},
+			{ID: 4, Commit: model.Commit{Repository: "vibioh/herodote", Hash: "d4", Type: "feat", Content: "Stream, fixes #2"}},
+			{ID: 6, Commit: model.Commit{Repository: "vibioh/herodote", Hash: "f6", Type: "feat"}},
+			// its transaction committed after the one of the higher id
+			{ID: 5, Commit: model.Commit{Repository: "vibioh/herodote", Hash: "e5", Type: "feat"}},
+			{ID: 6, Commit: model.Commit{Repository: "vibioh/herodote", Hash: "f6", Type: "feat"}},
+		},
+	}
+
+	withHeader := httptest.NewRequest(http.MethodGet, "/commits/stream?repository=vibioh/herodote", nil)
+	withHeader.Header.Set(lastEventIDHeader, "1")
+
+	cases := map[string]struct {
+		request    *http.Request
+		wantStatus int
+		wantIDs    []string
+	}{
+		"live": {
+			httptest.NewRequest(http.MethodGet, "/commits/stream", nil),
+			http.StatusOK,
+			[]string{"3", "4", "6", "5"},
+		},
+		"resume": {
+			withHeader,
+			http.StatusOK,
+			[]string{"3", "4", "6", "5"},
+		},
+		"resume from query": {
+			httptest.NewRequest(http.MethodGet, "/commits/stream?lastEventId=0&type=fix", nil),
+			http.StatusOK,
+			[]string{"3"},
+		},
+		"filtered replay": {
+			httptest.NewRequest(http.MethodGet, "/commits/stream?lastEventId=1&type=fix", nil),
+			http.StatusOK,
+			[]string{"2", "3"},
+		},
+		"invalid id": {
+			httptest.NewRequest(http.MethodGet, "/commits/stream?lastEventId=last", nil),
+			http.StatusBadRequest,
+			nil,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			instance := App{storeApp: store, vocabulary: vocabularyApp}
+
+			writer := httptest.NewRecorder()
+			instance.Handler().ServeHTTP(writer, tc.request)
+
+			if writer.Code != tc.wantStatus {
+				t.Fatalf("handleStream() = %d, want %d", writer.Code, tc.wantStatus)
+			}
+
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			if got := writer.Header().Get("Content-Type"); got != "text/event-stream" {
+				t.Errorf("handleStream() Content-Type = `%s`, want `text/event-stream`", got)
+			}
+
+			var gotIDs []string
+			for _, line := range strings.Split(writer.Body.String(), "\n") {
+				if id, ok := strings.CutPrefix(line, "id: "); ok {
+					gotIDs = append(gotIDs, id)
+				}
+			}
+
+			if strings.Join(gotIDs, ",") != strings.Join(tc.wantIDs, ",") {
+				t.Errorf("handleStream() ids = %v, want %v in `%s`", gotIDs, tc.wantIDs, writer.Body.String())
+			}
+		})
+	}
+}
+
+func TestRecentIDs(t *testing.T) {
+	instance := newRecentIDs(2)
+
+	var got []bool
+	for _, id := range []uint64{3, 1, 3, 2, 1, 3} {
+		got = append(got, instance.add(id))
+	}
+
+	// 3 is forgotten once 1 and 2 are sent
+	want := []bool{true, true, false, true, false, true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("add() = %v, want %v", got, want)
+	}
+}
+
+func TestWriteEvent(t *testing.T) {
+	var output strings.Builder
+
+	if err := writeEvent(&output, 4, model.Commit{Repository: "vibioh/herodote", Hash: "d4", Type: "feat"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "id: 4\nevent: commit\ndata: {\"date\":\"0001-01-01T00:00:00Z\",\"hash\":\"d4\",\"type\":\"feat\",\"component\":\"\",\"content\":\"\",\"remote\":\"\",\"repository\":\"vibioh/herodote\",\"breaking\":false,\"revert\":false}\n\n"
+	if got := output.String(); got != want {
+		t.Errorf("writeEvent() = `%s`, want `%s`", got, want)
+	}
+}
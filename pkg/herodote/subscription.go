@@ -0,0 +1,88 @@
+package herodote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+)
+
+const subscriptionsPath = "/subscriptions"
+
+// subscriptionsOwner returns the owner whose subscriptions are managed, empty for every owner with the admin token
+func subscriptionsOwner(ctx context.Context) string {
+	token, _ := TokenFromContext(ctx)
+	if token.Admin {
+		return ""
+	}
+
+	return token.Name
+}
+
+func (a App) handleSubscriptions(w http.ResponseWriter, r *http.Request) {
+	rawID := strings.Trim(strings.TrimPrefix(r.URL.Path, subscriptionsPath), "/")
+
+	switch {
+	case r.Method == http.MethodGet && len(rawID) == 0:
+		subscriptions, err := a.storeApp.ListSubscriptions(r.Context(), subscriptionsOwner(r.Context()))
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+
+		httpjson.WriteArray(w, http.StatusOK, subscriptions)
+	case r.Method == http.MethodPost && len(rawID) == 0:
+		a.handleCreateSubscription(w, r)
+	case r.Method == http.MethodDelete && len(rawID) != 0:
+		id, err := strconv.ParseUint(rawID, 10, 64)
+		if err != nil {
+			httperror.BadRequest(w, fmt.Errorf("subscription's id must be a number: %w", err))
+			return
+		}
+
+		if err = a.storeApp.DeleteSubscription(r.Context(), id, subscriptionsOwner(r.Context())); !httperror.HandleError(w, err) {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (a App) handleCreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var subscription model.Subscription
+	if err := httpjson.Parse(r, &subscription); err != nil {
+		writeParseError(w, err)
+		return
+	}
+
+	subscription = subscription.Sanitize()
+	if err := subscription.Check(); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	subscription.Filter.Types = a.vocabulary.ResolveTypes(subscription.Filter.Types)
+	subscription.Filter.Components = a.vocabulary.ResolveComponents(subscription.Filter.Components)
+
+	token, _ := TokenFromContext(r.Context())
+	subscription.Owner = token.Name
+	subscription.Tenant = token.Tenant
+	subscription.LastSent = nil
+
+	id, err := a.storeApp.CreateSubscription(r.Context(), subscription)
+	if err != nil {
+		httperror.InternalServerError(w, fmt.Errorf("create subscription: %w", err))
+		return
+	}
+
+	subscription.ID = id
+	subscription.CreationDate = time.Now()
+
+	httpjson.Write(w, http.StatusCreated, subscription)
+}
@@ -17,6 +17,8 @@ const (
 	weeksInMonth = float64(4)
 	monthsInYear = float64(12)
 	dayDuration  = time.Hour * 24
+
+	shortHashLength = 8
 )
 
 var (
@@ -54,6 +56,7 @@ var (
 		},
 		"contains":           contains,
 		"dateDistanceInDays": diffInDays,
+		"shortHash":          shortHash,
 		"toggleParam": func(path string, params url.Values, name, value string) string {
 			safeValues := url.Values{}
 			done := false
@@ -95,6 +98,15 @@ func contains(arr []string, value string) bool {
 	return false
 }
 
+// shortHash abbreviates a hash for display
+func shortHash(hash string) string {
+	if len(hash) > shortHashLength {
+		return hash[:shortHashLength]
+	}
+
+	return hash
+}
+
 func diffInDays(date, now time.Time) string {
 	beginNow := now.Truncate(dayDuration)
 	beginDate := date.Truncate(dayDuration)
@@ -0,0 +1,218 @@
+package herodote
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/oidc"
+	"github.com/ViBiOh/httputils/v4/pkg/cntxt"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
+)
+
+const (
+	tokensPath   = "/tokens"
+	secretLength = 32
+	touchTimeout = time.Second * 5
+)
+
+type tokenKey struct{}
+
+type createdToken struct {
+	Secret string `json:"token"`
+	model.Token
+}
+
+func withToken(ctx context.Context, token model.Token) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// TokenFromContext returns the token that authenticated the request, if any
+func TokenFromContext(ctx context.Context) (model.Token, bool) {
+	token, ok := ctx.Value(tokenKey{}).(model.Token)
+	return token, ok
+}
+
+func (a App) authenticate(r *http.Request) (model.Token, error) {
+	secret := r.Header.Get("Authorization")
+	if len(secret) == 0 {
+		return model.Token{}, ErrAuthentificationFailed
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(a.secret)) == 1 {
+		return model.AdminToken, nil
+	}
+
+	hash := model.HashToken(secret)
+
+	token, storedHash, err := a.storeApp.GetTokenByHash(r.Context(), hash)
+	if err != nil {
+		return model.Token{}, fmt.Errorf("get token: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(storedHash), []byte(hash)) != 1 {
+		return model.Token{}, ErrAuthentificationFailed
+	}
+
+	go func(ctx context.Context) {
+		ctx, cancel := context.WithTimeout(ctx, touchTimeout)
+		defer cancel()
+
+		if err := a.storeApp.TouchToken(ctx, token.Name); err != nil {
+			logger.WithField("token", token.Name).Error("touch token: %s", err)
+		}
+	}(cntxt.WithoutDeadline(r.Context()))
+
+	return token, nil
+}
+
+func (a App) handleTokens(w http.ResponseWriter, r *http.Request) {
+	if token, _ := TokenFromContext(r.Context()); !token.Admin {
+		httperror.Forbidden(w)
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, tokensPath), "/")
+
+	switch {
+	case r.Method == http.MethodGet && len(name) == 0:
+		a.handleListTokens(w, r)
+	case r.Method == http.MethodPost && len(name) == 0:
+		a.handleCreateToken(w, r)
+	case r.Method == http.MethodDelete && len(name) != 0:
+		if err := a.storeApp.DeleteToken(r.Context(), name); !httperror.HandleError(w, err) {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (a App) handleListTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := a.storeApp.ListTokens(r.Context())
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	httpjson.WriteArray(w, http.StatusOK, tokens)
+}
+
+func (a App) handleCreateToken(w http.ResponseWriter, r *http.Request) {
+	var token model.Token
+	if err := httpjson.Parse(r, &token); err != nil {
+		writeParseError(w, err)
+		return
+	}
+
+	token = token.Sanitize()
+	if err := token.Check(); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	if err = a.storeApp.CreateToken(r.Context(), token, model.HashToken(secret)); err != nil {
+		httperror.HandleError(w, fmt.Errorf("create token `%s`: %w", token.Name, err))
+		return
+	}
+
+	token.CreationDate = time.Now()
+	httpjson.Write(w, http.StatusCreated, createdToken{
+		Token:  token,
+		Secret: secret,
+	})
+}
+
+func generateSecret() (string, error) {
+	raw := make([]byte, secretLength)
+
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate secret: %w", err)
+	}
+
+	return hex.EncodeToString(raw), nil
+}
+
+// checkRepositoryAccess returns a forbidden error if the token's patterns don't allow the repository, or if it's private to another tenant
+func (a App) checkRepositoryAccess(ctx context.Context, repository string) error {
+	token, ok := TokenFromContext(ctx)
+	if !ok || !token.Allows(repository) {
+		return httpModel.WrapForbidden(fmt.Errorf("token `%s` is not allowed to write to `%s`", token.Name, repository))
+	}
+
+	if token.Admin {
+		return nil
+	}
+
+	privates, err := a.storeApp.PrivateRepositories(ctx)
+	if err != nil {
+		return fmt.Errorf("list private repositories: %w", err)
+	}
+
+	if tenant, private := privates[repository]; private && tenant != token.Tenant {
+		return httpModel.WrapForbidden(fmt.Errorf("token `%s` is not allowed to write to `%s`, private to another tenant", token.Name, repository))
+	}
+
+	return nil
+}
+
+// readerVisibility returns the tenants whose private repositories are visible by the reader of the request, see model.Token.Visibility
+func readerVisibility(ctx context.Context) []string {
+	token, ok := TokenFromContext(ctx)
+	if !ok {
+		session, logged := oidc.FromContext(ctx)
+		if logged && session.All {
+			return nil
+		}
+
+		// an empty visibility never means every repository, only the public ones are visible
+		if logged && len(session.Visibility) != 0 {
+			return session.Visibility
+		}
+	}
+
+	return token.Visibility()
+}
+
+// commitTenant returns the tenant of a saved commit: the one of the token, or the requested one with the admin token
+func commitTenant(ctx context.Context, requested string) (string, error) {
+	token, _ := TokenFromContext(ctx)
+	if token.Admin {
+		return requested, nil
+	}
+
+	if len(requested) != 0 && requested != token.Tenant {
+		return "", httpModel.WrapForbidden(fmt.Errorf("token `%s` is not allowed to write to tenant `%s`", token.Name, requested))
+	}
+
+	return token.Tenant, nil
+}
+
+// checkRepositoryVisibility returns a not found error if the repository is private to another tenant than the reader's one
+func (a App) checkRepositoryVisibility(ctx context.Context, repository string) error {
+	privates, err := a.storeApp.PrivateRepositories(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !privates.Visible(readerVisibility(ctx), repository) {
+		return httpModel.WrapNotFound(fmt.Errorf("repository `%s` not found", repository))
+	}
+
+	return nil
+}
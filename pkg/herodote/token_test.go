@@ -0,0 +1,183 @@
+package herodote
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/oidc"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
+)
+
+func TestReaderVisibility(t *testing.T) {
+	cases := map[string]struct {
+		ctx  context.Context
+		want []string
+	}{
+		"anonymous": {
+			context.Background(),
+			[]string{""},
+		},
+		"token": {
+			withToken(context.Background(), model.Token{Name: "ci", Tenant: "acme"}),
+			[]string{"acme"},
+		},
+		"session": {
+			oidc.WithSession(context.Background(), oidc.Session{Visibility: []string{"acme", "globex/billing*"}}),
+			[]string{"acme", "globex/billing*"},
+		},
+		"session of every repository": {
+			oidc.WithSession(context.Background(), oidc.Session{All: true}),
+			nil,
+		},
+		"session without visibility": {
+			oidc.WithSession(context.Background(), oidc.Session{}),
+			[]string{""},
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := readerVisibility(tc.ctx); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("readerVisibility() = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+// writeStore keeps `acme/billing` private to the acme tenant and counts writes
+type writeStore struct {
+	Store
+	writes *int
+}
+
+func (s writeStore) PrivateRepositories(_ context.Context) (model.PrivateRepositories, error) {
+	return model.PrivateRepositories{"acme/billing": "acme"}, nil
+}
+
+func (s writeStore) SaveCommit(_ context.Context, _ model.Commit) (bool, error) {
+	*s.writes++
+	return true, nil
+}
+
+func (s writeStore) GetCommit(_ context.Context, repository, hash string) (model.Commit, error) {
+	return model.Commit{Hash: hash, Type: "feat", Content: "Add README.md", Date: time.Now(), Remote: "github.com", Repository: repository}, nil
+}
+
+func (s writeStore) UpdateCommit(_ context.Context, _, _ string, _ model.Commit) error {
+	*s.writes++
+	return nil
+}
+
+func (s writeStore) DeleteCommit(_ context.Context, _, _ string) error {
+	*s.writes++
+	return nil
+}
+
+func TestWriteAccess(t *testing.T) {
+	fs := flag.NewFlagSet("write", flag.ContinueOnError)
+	vocabularyApp, err := vocabulary.New(vocabulary.Flags(fs, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acme := model.Token{Name: "ci-acme", Patterns: []string{"*"}, Tenant: "acme"}
+	globex := model.Token{Name: "ci-globex", Patterns: []string{"*"}, Tenant: "globex"}
+
+	commit := func(repository string) string {
+		return fmt.Sprintf(`{"hash":"1a2bc34d","type":"feat","content":"Add README.md","date":"2023-01-01T00:00:00Z","remote":"github.com","repository":"%s"}`, repository)
+	}
+
+	cases := map[string]struct {
+		token      model.Token
+		method     string
+		url        string
+		body       string
+		wantStatus int
+	}{
+		"post of the tenant": {
+			acme,
+			http.MethodPost,
+			"/commits",
+			commit("acme/billing"),
+			http.StatusCreated,
+		},
+		"post of another tenant": {
+			globex,
+			http.MethodPost,
+			"/commits",
+			commit("acme/billing"),
+			http.StatusForbidden,
+		},
+		"post of a public repository": {
+			globex,
+			http.MethodPost,
+			"/commits",
+			commit("vibioh/herodote"),
+			http.StatusCreated,
+		},
+		"patch of the tenant": {
+			acme,
+			http.MethodPatch,
+			"/commits/acme/billing/1a2bc34d",
+			`{"type":"fix"}`,
+			http.StatusOK,
+		},
+		"patch of another tenant": {
+			globex,
+			http.MethodPatch,
+			"/commits/acme/billing/1a2bc34d",
+			`{"type":"fix"}`,
+			http.StatusForbidden,
+		},
+		"patch moving to another tenant": {
+			globex,
+			http.MethodPatch,
+			"/commits/globex/billing/1a2bc34d",
+			`{"repository":"acme/billing"}`,
+			http.StatusForbidden,
+		},
+		"delete of another tenant": {
+			globex,
+			http.MethodDelete,
+			"/commits/acme/billing/1a2bc34d",
+			"",
+			http.StatusForbidden,
+		},
+		"delete by admin": {
+			model.AdminToken,
+			http.MethodDelete,
+			"/commits/acme/billing/1a2bc34d",
+			"",
+			http.StatusNoContent,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			var writes int
+			instance := App{storeApp: writeStore{writes: &writes}, vocabulary: vocabularyApp}
+
+			request := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			request = request.WithContext(withToken(request.Context(), tc.token))
+
+			writer := httptest.NewRecorder()
+			instance.handleCommits(writer, request)
+
+			if writer.Code != tc.wantStatus {
+				t.Errorf("handleCommits() = %d, want %d: %s", writer.Code, tc.wantStatus, writer.Body.String())
+			}
+
+			if wantWrites := tc.wantStatus != http.StatusForbidden; (writes != 0) != wantWrites {
+				t.Errorf("handleCommits() wrote %d times, want writes %t", writes, wantWrites)
+			}
+		})
+	}
+}
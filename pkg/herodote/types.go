@@ -0,0 +1,18 @@
+package herodote
+
+import (
+	"net/http"
+
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+)
+
+const typesPath = "/types"
+
+func (a App) handleTypes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	httpjson.WriteArray(w, http.StatusOK, a.vocabulary.Types())
+}
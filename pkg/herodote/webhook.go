@@ -0,0 +1,104 @@
+package herodote
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/query"
+)
+
+const (
+	webhooksPath = "/webhooks"
+	failuresPath = "/failures"
+)
+
+func (a App) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	if token, _ := TokenFromContext(r.Context()); !token.Admin {
+		httperror.Forbidden(w)
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, webhooksPath), "/")
+
+	switch {
+	case r.Method == http.MethodGet && len(name) == 0:
+		a.handleListWebhooks(w, r)
+	case r.Method == http.MethodPost && len(name) == 0:
+		a.handleCreateWebhook(w, r)
+	case r.Method == http.MethodGet && strings.HasSuffix(name, failuresPath):
+		a.handleListWebhookFailures(w, r, strings.TrimSuffix(name, failuresPath))
+	case r.Method == http.MethodDelete && len(name) != 0:
+		if err := a.storeApp.DeleteWebhook(r.Context(), name); !httperror.HandleError(w, err) {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (a App) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := a.storeApp.ListWebhooks(r.Context())
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	httpjson.WriteArray(w, http.StatusOK, webhooks)
+}
+
+func (a App) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var webhook model.Webhook
+	if err := httpjson.Parse(r, &webhook); err != nil {
+		writeParseError(w, err)
+		return
+	}
+
+	webhook = webhook.Sanitize()
+	if err := webhook.Check(); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	webhook.Filter.Types = a.vocabulary.ResolveTypes(webhook.Filter.Types)
+	webhook.Filter.Components = a.vocabulary.ResolveComponents(webhook.Filter.Components)
+
+	if len(webhook.Secret) == 0 {
+		secret, err := generateSecret()
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+
+		webhook.Secret = secret
+	}
+
+	if err := a.storeApp.CreateWebhook(r.Context(), webhook); err != nil {
+		httperror.HandleError(w, fmt.Errorf("create webhook `%s`: %w", webhook.Name, err))
+		return
+	}
+
+	webhook.CreationDate = time.Now()
+	httpjson.Write(w, http.StatusCreated, webhook)
+}
+
+func (a App) handleListWebhookFailures(w http.ResponseWriter, r *http.Request, name string) {
+	pagination, err := query.ParsePagination(r, model.DefaultPageSize, 100)
+	if err != nil {
+		httperror.BadRequest(w, httpModel.WrapInvalid(err))
+		return
+	}
+
+	failures, err := a.storeApp.ListWebhookFailures(r.Context(), strings.Trim(name, "/"), pagination.PageSize)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	httpjson.WriteArray(w, http.StatusOK, failures)
+}
@@ -0,0 +1,70 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+)
+
+// batch saves commits by transactions of the configured size and counts them
+type batch struct {
+	report  Report
+	app     App
+	commits []model.Commit
+}
+
+func (a App) newBatch() *batch {
+	return &batch{
+		app:     a,
+		commits: make([]model.Commit, 0, a.batchSize),
+	}
+}
+
+func (b *batch) invalid(position string, err error) {
+	b.report.Invalid++
+	logger.Warn("%s is skipped: %s", position, err)
+}
+
+func (b *batch) add(ctx context.Context, commit model.Commit) error {
+	if b.commits = append(b.commits, commit); uint(len(b.commits)) < b.app.batchSize {
+		return nil
+	}
+
+	return b.flush(ctx)
+}
+
+func (b *batch) flush(ctx context.Context) error {
+	if len(b.commits) == 0 {
+		return nil
+	}
+
+	if !b.app.dryRun {
+		created, updated, err := b.app.store.ImportCommits(ctx, b.commits)
+		if err != nil {
+			return fmt.Errorf("import commits: %w", err)
+		}
+
+		b.report.Created += created
+		b.report.Updated += updated
+	}
+
+	b.commits = b.commits[:0]
+	logger.Info("Import in progress: %s", b.report)
+
+	return nil
+}
+
+// close saves the remaining commits, an error is returned if some were invalid
+func (b *batch) close(ctx context.Context) (Report, error) {
+	if err := b.flush(ctx); err != nil {
+		return b.report, err
+	}
+
+	if b.report.Invalid != 0 {
+		return b.report, fmt.Errorf("%d invalid commits skipped", b.report.Invalid)
+	}
+
+	return b.report, nil
+}
@@ -0,0 +1,134 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+// maxLineSize is the maximum size of a NDJSON line
+const maxLineSize = 1 << 20
+
+// invalidError is a record that can't be parsed, the following ones can still be read
+type invalidError struct {
+	err error
+}
+
+func (e invalidError) Error() string {
+	return e.err.Error()
+}
+
+func (e invalidError) Unwrap() error {
+	return e.err
+}
+
+type decoder interface {
+	next() (model.CommitRecord, error)
+	line() uint64
+}
+
+func newDecoder(reader io.Reader, format string) (decoder, error) {
+	switch format {
+	case formatNDJSON:
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+		return &ndjsonDecoder{scanner: scanner}, nil
+	case formatCSV:
+		return newCSVDecoder(reader)
+	default:
+		return nil, fmt.Errorf("import format `%s` is unknown", format)
+	}
+}
+
+type ndjsonDecoder struct {
+	scanner *bufio.Scanner
+	count   uint64
+}
+
+func (d *ndjsonDecoder) next() (model.CommitRecord, error) {
+	var record model.CommitRecord
+
+	for d.scanner.Scan() {
+		d.count++
+
+		content := d.scanner.Bytes()
+		if len(bytes.TrimSpace(content)) == 0 {
+			continue
+		}
+
+		if err := json.Unmarshal(content, &record); err != nil {
+			return record, invalidError{fmt.Errorf("parse json: %w", err)}
+		}
+
+		return record, nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return record, err
+	}
+
+	return record, io.EOF
+}
+
+func (d *ndjsonDecoder) line() uint64 {
+	return d.count
+}
+
+type csvDecoder struct {
+	reader  *csv.Reader
+	header  map[string]int
+	current uint64
+}
+
+func newCSVDecoder(reader io.Reader) (*csvDecoder, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	row, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	header := make(map[string]int, len(row))
+	for index, name := range row {
+		header[strings.ToLower(strings.TrimSpace(name))] = index
+	}
+
+	return &csvDecoder{reader: csvReader, header: header}, nil
+}
+
+func (d *csvDecoder) next() (model.CommitRecord, error) {
+	row, err := d.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			d.current = uint64(parseErr.StartLine)
+
+			return model.CommitRecord{}, invalidError{err}
+		}
+
+		return model.CommitRecord{}, err
+	}
+
+	line, _ := d.reader.FieldPos(0)
+	d.current = uint64(line)
+
+	record, err := model.ParseCommitRecord(d.header, row)
+	if err != nil {
+		return record, invalidError{err}
+	}
+
+	return record, nil
+}
+
+func (d *csvDecoder) line() uint64 {
+	return d.current
+}
@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/herodote/pkg/git"
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+// GitSource is a local clone or a bare mirror of a repository
+type GitSource struct {
+	Path       string
+	Remote     string
+	Repository string
+	Refs       []string
+}
+
+type GitConfig struct {
+	path       *string
+	remote     *string
+	repository *string
+	refs       *string
+}
+
+func GitFlags(fs *flag.FlagSet, prefix string) GitConfig {
+	return GitConfig{
+		path:       flags.New("Path", "Path of a local clone or a bare mirror").Prefix(prefix).DocPrefix("git").String(fs, ".", nil),
+		remote:     flags.New("Remote", "Remote of the repository, from its first remote if empty").Prefix(prefix).DocPrefix("git").String(fs, "", nil),
+		repository: flags.New("Repository", "Name of the repository, from its first remote if empty").Prefix(prefix).DocPrefix("git").String(fs, "", nil),
+		refs:       flags.New("Refs", "Refs to walk, comma separated, HEAD if empty").Prefix(prefix).DocPrefix("git").String(fs, "", nil),
+	}
+}
+
+// NewGitSource creates the source, remote and repository are read from the first remote of the repository when not given
+func NewGitSource(ctx context.Context, config GitConfig) (GitSource, error) {
+	source := GitSource{
+		Path:       strings.TrimSpace(*config.path),
+		Remote:     strings.TrimSpace(*config.remote),
+		Repository: strings.TrimSpace(*config.repository),
+	}
+
+	for _, ref := range strings.Split(*config.refs, ",") {
+		if ref = strings.TrimSpace(ref); len(ref) != 0 {
+			source.Refs = append(source.Refs, ref)
+		}
+	}
+
+	if len(source.Remote) != 0 && len(source.Repository) != 0 {
+		return source, nil
+	}
+
+	remote, repository, err := git.Remote(ctx, source.Path)
+	if err != nil {
+		return source, fmt.Errorf("remote and repository are required: %w", err)
+	}
+
+	if len(source.Remote) == 0 {
+		source.Remote = remote
+	}
+
+	if len(source.Repository) == 0 {
+		source.Repository = repository
+	}
+
+	return source, nil
+}
+
+// ImportGit saves conventional commits reachable from the refs of the source, with the branch reaching them.
+// Commits already saved for the repository with this branch are skipped, to resume an interrupted import.
+func (a App) ImportGit(ctx context.Context, source GitSource) (Report, error) {
+	repository := model.Commit{Repository: source.Repository}.Sanitize().Repository
+
+	existing, err := a.store.ListCommitBranches(ctx, repository)
+	if err != nil {
+		return Report{}, fmt.Errorf("list existing commits: %w", err)
+	}
+
+	batch := a.newBatch()
+
+	err = git.Log(ctx, source.Path, source.Refs, func(item git.Commit) error {
+		batch.report.Read++
+
+		branch := branchName(item.Source)
+
+		if isSaved(existing, strings.ToLower(item.Hash), branch) {
+			batch.report.Skipped++
+			return nil
+		}
+
+		parsed, ok := a.vocabulary.ParseSubject(item.Subject)
+		if !ok {
+			batch.report.Skipped++
+			return nil
+		}
+
+		_, pickedFrom := a.vocabulary.ExtractCherryPick(item.Body)
+
+		record := model.CommitRecord{
+			Date:       item.Date,
+			Repository: source.Repository,
+			Remote:     source.Remote,
+			Hash:       item.Hash,
+			Type:       parsed.Type,
+			Component:  parsed.Component,
+			Content:    parsed.Content,
+			PickedFrom: pickedFrom,
+			Paths:      item.Paths,
+			Breaking:   parsed.Breaking,
+			Revert:     parsed.Revert,
+		}
+
+		if len(branch) != 0 {
+			record.Branches = []string{branch}
+		}
+
+		commit, err := a.commit(record)
+		if err != nil {
+			batch.invalid(fmt.Sprintf("commit `%s`", item.Hash), err)
+			return nil
+		}
+
+		return batch.add(ctx, commit)
+	})
+	if err != nil {
+		return batch.report, err
+	}
+
+	return batch.close(ctx)
+}
+
+func isSaved(existing map[string][]string, hash, branch string) bool {
+	branches, ok := existing[hash]
+	if !ok || len(branch) == 0 {
+		return ok
+	}
+
+	for _, item := range branches {
+		if item == branch {
+			return true
+		}
+	}
+
+	return false
+}
+
+// branchName returns the branch of a ref reaching a commit, empty for HEAD, tags or hashes
+func branchName(source string) string {
+	if name, ok := strings.CutPrefix(source, "refs/heads/"); ok {
+		return name
+	}
+
+	if name, ok := strings.CutPrefix(source, "refs/remotes/"); ok {
+		if _, branch, found := strings.Cut(name, "/"); found {
+			return branch
+		}
+	}
+
+	return ""
+}
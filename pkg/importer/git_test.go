@@ -0,0 +1,155 @@
+package importer
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+type gitRunner struct {
+	t    *testing.T
+	path string
+}
+
+func (g gitRunner) run(args ...string) string {
+	g.t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-C", g.path}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=herodote", "GIT_AUTHOR_EMAIL=herodote@localhost", "GIT_COMMITTER_NAME=herodote", "GIT_COMMITTER_EMAIL=herodote@localhost", "GIT_CONFIG_GLOBAL=/dev/null")
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		g.t.Fatalf("git %v: %s: %s", args, err, output)
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+func (g gitRunner) commit(file, message string) string {
+	g.t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(g.path, file)), 0o700); err != nil {
+		g.t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(g.path, file), []byte(message), 0o600); err != nil {
+		g.t.Fatal(err)
+	}
+
+	g.run("add", file)
+	g.run("commit", "--quiet", "-m", message)
+
+	return g.run("rev-parse", "HEAD")
+}
+
+// gitRepository creates a repository with a `release` branch having a fix picked from `main`, it returns the hash of this fix
+func gitRepository(t *testing.T) (string, string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	g := gitRunner{t: t, path: t.TempDir()}
+
+	g.run("init", "--quiet")
+	g.run("symbolic-ref", "HEAD", "refs/heads/main")
+	g.run("remote", "add", "origin", "git@github.com:ViBiOh/Herodote.git")
+
+	g.commit("README.md", "docs: Add README")
+	g.commit("pkg/git/git.go", "feat(git)!: Read log, fixes #12")
+	g.run("branch", "release")
+	g.commit("notes.txt", "Update notes")
+	g.commit("pkg/git/git_test.go", "wip: Test log")
+	fix := g.commit("pkg/git/body.go", "fix(git): Read body")
+
+	g.run("checkout", "--quiet", "release")
+	g.run("cherry-pick", "-x", fix)
+	g.run("checkout", "--quiet", "main")
+
+	return g.path, fix
+}
+
+func TestImportGit(t *testing.T) {
+	path, fix := gitRepository(t)
+	refs := "main,release"
+
+	source, err := NewGitSource(context.Background(), GitConfig{path: &path, remote: new(string), repository: new(string), refs: &refs})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if source.Remote != "github.com" || source.Repository != "ViBiOh/Herodote" {
+		t.Errorf("NewGitSource() = %+v, want remote and repository of origin", source)
+	}
+
+	store := &stubStore{saved: make(map[string]bool), branches: make(map[string][]string)}
+	app := newTestApp(t, store)
+
+	report, err := app.ImportGit(context.Background(), source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (Report{Read: 6, Created: 4, Skipped: 2}); report != want {
+		t.Errorf("ImportGit() = %s, want %s", report, want)
+	}
+
+	var picked []model.Commit
+	for _, commit := range store.commits {
+		if len(commit.PickedFrom) != 0 {
+			picked = append(picked, commit)
+		}
+	}
+
+	if len(picked) != 1 || picked[0].PickedFrom != fix || picked[0].Content != "Read body" || !reflect.DeepEqual(picked[0].Branches, []string{"release"}) {
+		t.Errorf("ImportGit() picked = %+v, want the fix picked from `%s` on release", picked, fix)
+	}
+
+	report, err = app.ImportGit(context.Background(), source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (Report{Read: 6, Skipped: 6}); report != want {
+		t.Errorf("ImportGit() resumed = %s, want %s", report, want)
+	}
+}
+
+func TestBranchName(t *testing.T) {
+	cases := map[string]struct {
+		source string
+		want   string
+	}{
+		"branch": {
+			"refs/heads/release/1.x",
+			"release/1.x",
+		},
+		"remote": {
+			"refs/remotes/origin/main",
+			"main",
+		},
+		"head": {
+			"HEAD",
+			"",
+		},
+		"tag": {
+			"refs/tags/v1.0.0",
+			"",
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := branchName(tc.source); got != tc.want {
+				t.Errorf("branchName() = `%s`, want `%s`", got, tc.want)
+			}
+		})
+	}
+}
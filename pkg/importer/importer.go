@@ -0,0 +1,172 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
+)
+
+const (
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+)
+
+// Store saves commits, inserting or updating them by repository and hash, and lists existing ones
+type Store interface {
+	ImportCommits(ctx context.Context, commits []model.Commit) (uint64, uint64, error)
+	ListCommitBranches(ctx context.Context, repository string) (map[string][]string, error)
+}
+
+// Report counts commits of an import
+type Report struct {
+	Read    uint64
+	Created uint64
+	Updated uint64
+	Skipped uint64
+	Invalid uint64
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("%d read, %d created, %d updated, %d skipped, %d invalid", r.Read, r.Created, r.Updated, r.Skipped, r.Invalid)
+}
+
+type App struct {
+	store            Store
+	vocabulary       vocabulary.App
+	format           string
+	batchSize        uint
+	maxContentLength uint
+	dryRun           bool
+}
+
+type Config struct {
+	format           *string
+	batchSize        *uint
+	maxContentLength *uint
+	dryRun           *bool
+}
+
+func Flags(fs *flag.FlagSet, prefix string) Config {
+	return Config{
+		format:           flags.New("Format", "Format of the dump: ndjson or csv, guessed from the file extension if empty").Prefix(prefix).DocPrefix("import").String(fs, "", nil),
+		batchSize:        flags.New("BatchSize", "Commits saved by transaction").Prefix(prefix).DocPrefix("import").Uint(fs, 500, nil),
+		maxContentLength: flags.New("MaxContentLength", "Maximum number of characters of a commit's content, 0 to disable").Prefix(prefix).DocPrefix("import").Uint(fs, model.DefaultMaxContentLength, nil),
+		dryRun:           flags.New("DryRun", "Validate the dump without saving it").Prefix(prefix).DocPrefix("import").Bool(fs, false, nil),
+	}
+}
+
+func New(config Config, store Store, vocabularyApp vocabulary.App) (App, error) {
+	app := App{
+		store:            store,
+		vocabulary:       vocabularyApp,
+		format:           strings.ToLower(strings.TrimSpace(*config.format)),
+		batchSize:        *config.batchSize,
+		maxContentLength: *config.maxContentLength,
+		dryRun:           *config.dryRun,
+	}
+
+	if len(app.format) != 0 && app.format != formatNDJSON && app.format != formatCSV {
+		return App{}, fmt.Errorf("import format `%s` is unknown", app.format)
+	}
+
+	if app.batchSize == 0 {
+		return App{}, errors.New("import batch size must be positive")
+	}
+
+	return app, nil
+}
+
+// DryRun returns true if nothing is saved
+func (a App) DryRun() bool {
+	return a.dryRun
+}
+
+// Format returns the format of a dump, from the configuration or the extension of its name
+func (a App) Format(name string) string {
+	if len(a.format) != 0 {
+		return a.format
+	}
+
+	if strings.EqualFold(filepath.Ext(name), "."+formatCSV) {
+		return formatCSV
+	}
+
+	return formatNDJSON
+}
+
+// Import saves commits of the dump by batches, invalid commits are logged and skipped
+func (a App) Import(ctx context.Context, reader io.Reader, format string) (Report, error) {
+	records, err := newDecoder(reader, format)
+	if err != nil {
+		return Report{}, err
+	}
+
+	batch := a.newBatch()
+
+	for {
+		record, err := records.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var invalid invalidError
+		if err != nil && !errors.As(err, &invalid) {
+			return batch.report, fmt.Errorf("read line %d: %w", records.line(), err)
+		}
+
+		batch.report.Read++
+
+		var commit model.Commit
+		if err == nil {
+			commit, err = a.commit(record)
+		}
+
+		if err != nil {
+			batch.invalid(fmt.Sprintf("line %d", records.line()), err)
+			continue
+		}
+
+		if err = batch.add(ctx, commit); err != nil {
+			return batch.report, err
+		}
+	}
+
+	return batch.close(ctx)
+}
+
+// commit applies the vocabulary to the record, as a commit saved through the API
+func (a App) commit(record model.CommitRecord) (model.Commit, error) {
+	commit := record.Commit().Sanitize()
+	if len(commit.PickedFrom) == 0 {
+		commit.Content, commit.PickedFrom = a.vocabulary.ExtractCherryPick(commit.Content)
+	}
+
+	if err := commit.Check(a.maxContentLength); err != nil {
+		return commit, err
+	}
+
+	var err error
+	if commit.Type, err = a.vocabulary.ResolveType(commit.Type); err != nil {
+		return commit, err
+	}
+
+	if len(commit.Component) == 0 {
+		commit.Component = a.vocabulary.InferComponent(commit.Repository, commit.Paths)
+	}
+
+	commit.Component = a.vocabulary.ResolveComponent(commit.Component)
+
+	if len(commit.References) == 0 {
+		commit.References = a.vocabulary.ExtractReferences(commit.Content)
+	}
+
+	return commit, nil
+}
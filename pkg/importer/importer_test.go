@@ -0,0 +1,208 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/herodote/pkg/vocabulary"
+)
+
+type stubStore struct {
+	saved    map[string]bool
+	branches map[string][]string
+	commits  []model.Commit
+	batches  []int
+	err      error
+}
+
+func (s *stubStore) ImportCommits(_ context.Context, commits []model.Commit) (uint64, uint64, error) {
+	if s.err != nil {
+		return 0, 0, s.err
+	}
+
+	s.batches = append(s.batches, len(commits))
+	s.commits = append(s.commits, commits...)
+
+	var created, updated uint64
+
+	for _, commit := range commits {
+		key := commit.Repository + "@" + commit.Hash
+		if s.branches != nil {
+			s.branches[key] = append(s.branches[key], commit.Branches...)
+		}
+
+		if s.saved[key] {
+			updated++
+		} else {
+			created++
+			s.saved[key] = true
+		}
+	}
+
+	return created, updated, nil
+}
+
+func (s *stubStore) ListCommitBranches(_ context.Context, repository string) (map[string][]string, error) {
+	output := make(map[string][]string)
+
+	for key := range s.saved {
+		if name, hash, _ := strings.Cut(key, "@"); name == repository {
+			output[hash] = append(output[hash], s.branches[key]...)
+		}
+	}
+
+	return output, nil
+}
+
+func newTestApp(t *testing.T, store Store, args ...string) App {
+	t.Helper()
+
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	config := Flags(fs, "import")
+	vocabularyConfig := vocabulary.Flags(fs, "")
+
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	vocabularyApp, err := vocabulary.New(vocabularyConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app, err := New(config, store, vocabularyApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return app
+}
+
+const ndjsonDump = `{"date":"2023-07-20T06:00:00Z","repository":"vibioh/herodote","remote":"github.com","hash":"a1","type":"feat","content":"Add import, fixes #12","paths":["pkg/importer"],"refs":[],"id":1}
+
+{"date":"2023-07-21T06:00:00Z","repository":"vibioh/ketchup","remote":"github.com","hash":"b2","type":"fix","content":"Fix release"}
+{"date":"2023-07-21T06:00:00Z","repository":"vibioh/ketchup","remote":"github.com","hash":"c3","type":"fix"}
+{"date":"2023-07-22T06:00:00Z","repository":"vibioh/herodote","remote":"github.com","hash":"a1","type":"FEAT","content":"Add import"}
+`
+
+const csvDump = `hash,repository,remote,date,type,content,refs
+a1,vibioh/herodote,github.com,2023-07-20T06:00:00Z,feat,"Add import, with CSV",PLAT-1;#12
+b2,vibioh/ketchup,github.com,not a date,fix,Fix release,
+c3,vibioh/ketchup,github.com,2023-07-21T06:00:00Z,fix,Fix release,
+`
+
+func TestImport(t *testing.T) {
+	cases := map[string]struct {
+		dump        string
+		format      string
+		args        []string
+		storeErr    error
+		want        Report
+		wantBatches []int
+		wantErr     string
+	}{
+		"ndjson": {
+			ndjsonDump,
+			formatNDJSON,
+			[]string{"-importBatchSize", "2"},
+			nil,
+			Report{Read: 4, Created: 2, Updated: 1, Invalid: 1},
+			[]int{2, 1},
+			"1 invalid commits skipped",
+		},
+		"csv": {
+			csvDump,
+			formatCSV,
+			nil,
+			nil,
+			Report{Read: 3, Created: 2, Invalid: 1},
+			[]int{2},
+			"1 invalid commits skipped",
+		},
+		"dry run": {
+			ndjsonDump,
+			formatNDJSON,
+			[]string{"-importDryRun"},
+			nil,
+			Report{Read: 4, Invalid: 1},
+			nil,
+			"1 invalid commits skipped",
+		},
+		"invalid json": {
+			"{\"hash\":\n",
+			formatNDJSON,
+			nil,
+			nil,
+			Report{Read: 1, Invalid: 1},
+			nil,
+			"1 invalid commits skipped",
+		},
+		"store error": {
+			ndjsonDump,
+			formatNDJSON,
+			nil,
+			errors.New("timeout"),
+			Report{Read: 4, Invalid: 1},
+			nil,
+			"timeout",
+		},
+		"empty csv": {
+			"",
+			formatCSV,
+			nil,
+			nil,
+			Report{},
+			nil,
+			"read header",
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			store := &stubStore{saved: make(map[string]bool), err: tc.storeErr}
+
+			got, err := newTestApp(t, store, tc.args...).Import(context.Background(), strings.NewReader(tc.dump), tc.format)
+
+			if len(tc.wantErr) == 0 && err != nil {
+				t.Errorf("Import() error = %s", err)
+			} else if len(tc.wantErr) != 0 && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Errorf("Import() error = %v, want `%s`", err, tc.wantErr)
+			}
+
+			if got != tc.want {
+				t.Errorf("Import() = %s, want %s", got, tc.want)
+			}
+
+			if !reflect.DeepEqual(store.batches, tc.wantBatches) {
+				t.Errorf("Import() batches = %v, want %v", store.batches, tc.wantBatches)
+			}
+		})
+	}
+}
+
+func TestCommit(t *testing.T) {
+	app := newTestApp(t, &stubStore{})
+
+	got, err := app.commit(model.CommitRecord{Date: time.Now(), Repository: " ViBiOh/Herodote ", Remote: "github.com", Hash: "A1", Type: "Feat", Content: "Add import, fixes #12", Paths: []string{"./pkg/importer/"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Repository != "vibioh/herodote" || got.Hash != "a1" || got.Type != "feat" {
+		t.Errorf("commit() = %+v, want sanitized commit", got)
+	}
+
+	if !reflect.DeepEqual(got.Paths, []string{"pkg/importer"}) {
+		t.Errorf("commit() paths = %v, want cleaned paths", got.Paths)
+	}
+
+	if keys := model.ReferenceKeys(got.References); !reflect.DeepEqual(keys, []string{"#12"}) {
+		t.Errorf("commit() references = %v, want extracted references", keys)
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"net/http"
+	"time"
+)
+
+// AuditEntry records a write made through the API, by who and from where
+type AuditEntry struct {
+	Date       time.Time `json:"date"`
+	Actor      string    `json:"actor"`
+	Tenant     string    `json:"tenant,omitempty"`
+	IP         string    `json:"ip"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Repository string    `json:"repository,omitempty"`
+	Hash       string    `json:"hash,omitempty"`
+	Status     int       `json:"status"`
+	ID         uint64    `json:"id"`
+}
+
+// AuditFilter selects audit entries, empty fields match every entry
+type AuditFilter struct {
+	After      time.Time
+	Before     time.Time
+	Actor      string
+	Repository string
+	Method     string
+}
+
+// IsWriteMethod checks if the HTTP method changes data, and is audited
+func IsWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// Component describes a canonical component and the values normalized to it
+type Component struct {
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+func (c Component) Sanitize() Component {
+	c.Name = cleanString(c.Name)
+
+	aliases := make([]string, 0, len(c.Aliases))
+	for _, alias := range c.Aliases {
+		if alias = cleanString(alias); len(alias) != 0 {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	c.Aliases = aliases
+
+	return c
+}
+
+func (c Component) Check() error {
+	if len(c.Name) == 0 {
+		return fmt.Errorf("component's name is required (e.g. `api`)")
+	}
+
+	for _, pattern := range c.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("component's pattern `%s` is invalid: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// PathRule infers the component of a commit of matching repositories from its changed paths
+type PathRule struct {
+	Repository string `json:"repository"`
+	Pattern    string `json:"pattern"`
+	Component  string `json:"component"`
+}
+
+func (p PathRule) Sanitize() PathRule {
+	p.Repository = cleanString(p.Repository)
+	p.Component = cleanString(p.Component)
+
+	if len(p.Repository) == 0 {
+		p.Repository = "*"
+	}
+
+	return p
+}
+
+func (p PathRule) Check() error {
+	if _, err := path.Match(p.Repository, ""); err != nil {
+		return fmt.Errorf("path rule's repository `%s` is invalid: %w", p.Repository, err)
+	}
+
+	if len(p.Pattern) == 0 {
+		return fmt.Errorf("path rule's pattern is required (e.g. `^services/([^/]+)/`)")
+	}
+
+	if _, err := regexp.Compile(p.Pattern); err != nil {
+		return fmt.Errorf("path rule's pattern `%s` is invalid: %w", p.Pattern, err)
+	}
+
+	if len(p.Component) == 0 {
+		return fmt.Errorf("path rule's component is required (e.g. `$1`)")
+	}
+
+	return nil
+}
+
+// ComponentRewrite describes the rewrite of historical component values
+type ComponentRewrite struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Count uint64 `json:"count"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DeploymentPending = "pending"
+	DeploymentSuccess = "success"
+	DeploymentFailure = "failure"
+)
+
+// Deployment records a commit of a repository deployed to an environment
+type Deployment struct {
+	Date        time.Time `json:"date"`
+	CommitDate  time.Time `json:"-"`
+	Repository  string    `json:"repository"`
+	Environment string    `json:"environment"`
+	Hash        string    `json:"hash"`
+	Commit      string    `json:"commit,omitempty"`
+	Status      string    `json:"status"`
+	ID          uint64    `json:"id"`
+}
+
+func (d Deployment) Sanitize() Deployment {
+	d.Repository = cleanString(d.Repository)
+	d.Environment = cleanString(d.Environment)
+	d.Hash = cleanString(d.Hash)
+	d.Status = cleanString(d.Status)
+
+	if len(d.Status) == 0 {
+		d.Status = DeploymentSuccess
+	}
+
+	return d
+}
+
+func (d Deployment) Check() error {
+	if len(d.Repository) == 0 {
+		return fmt.Errorf("deployment's repository is required (e.g. `vibioh/herodote`)")
+	}
+
+	if len(d.Environment) == 0 {
+		return fmt.Errorf("deployment's environment is required (e.g. `production`)")
+	}
+
+	if len(d.Hash) == 0 {
+		return fmt.Errorf("deployment's hash is required (e.g. `1a2bc34d`)")
+	}
+
+	if d.Status != DeploymentPending && d.Status != DeploymentSuccess && d.Status != DeploymentFailure {
+		return fmt.Errorf("deployment's status must be `%s`, `%s` or `%s`", DeploymentPending, DeploymentSuccess, DeploymentFailure)
+	}
+
+	return nil
+}
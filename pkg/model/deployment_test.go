@@ -0,0 +1,35 @@
+package model
+
+import "testing"
+
+func TestDeploymentCheck(t *testing.T) {
+	cases := map[string]struct {
+		instance Deployment
+		wantErr  bool
+	}{
+		"default status": {
+			Deployment{Repository: " ViBiOh/herodote", Environment: "Production ", Hash: "1a2bc34d"},
+			false,
+		},
+		"failure": {
+			Deployment{Repository: "vibioh/herodote", Environment: "staging", Hash: "1a2bc34d", Status: "FAILURE"},
+			false,
+		},
+		"unknown status": {
+			Deployment{Repository: "vibioh/herodote", Environment: "staging", Hash: "1a2bc34d", Status: "done"},
+			true,
+		},
+		"no hash": {
+			Deployment{Repository: "vibioh/herodote", Environment: "staging"},
+			true,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if err := tc.instance.Sanitize().Check(); (err != nil) != tc.wantErr {
+				t.Errorf("Check() = %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
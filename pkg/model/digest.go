@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Digest summarizes commits saved during a period
+type Digest struct {
+	Since        time.Time          `json:"since"`
+	Until        time.Time          `json:"until"`
+	Repositories []RepositoryDigest `json:"repositories"`
+	Highlights   []Commit           `json:"highlights"`
+}
+
+// RepositoryDigest counts commits of a repository by type
+type RepositoryDigest struct {
+	Types      map[string]uint64 `json:"types"`
+	Repository string            `json:"repository"`
+	Count      uint64            `json:"count"`
+}
+
+// Count returns the count of commits of the digest
+func (d Digest) Count() uint64 {
+	var count uint64
+
+	for _, repository := range d.Repositories {
+		count += repository.Count
+	}
+
+	return count
+}
@@ -4,20 +4,31 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
-const DefaultPageSize = 50
+const (
+	DefaultPageSize = 50
+
+	// DefaultMaxContentLength is the default maximum number of characters of a commit's content
+	DefaultMaxContentLength = 10000
+)
 
 type Commit struct {
-	Date       time.Time `json:"date"`
-	Hash       string    `json:"hash"`
-	Type       string    `json:"type"`
-	Component  string    `json:"component"`
-	Content    string    `json:"content"`
-	Remote     string    `json:"remote"`
-	Repository string    `json:"repository"`
-	Breaking   bool      `json:"breaking"`
-	Revert     bool      `json:"revert"`
+	Date       time.Time   `json:"date"`
+	Hash       string      `json:"hash"`
+	Type       string      `json:"type"`
+	Component  string      `json:"component"`
+	Content    string      `json:"content"`
+	Remote     string      `json:"remote"`
+	Repository string      `json:"repository"`
+	PickedFrom string      `json:"pickedFrom,omitempty"`
+	Tenant     string      `json:"tenant,omitempty"`
+	Paths      []string    `json:"paths,omitempty"`
+	Branches   []string    `json:"branches,omitempty"`
+	References []Reference `json:"references,omitempty"`
+	Breaking   bool        `json:"breaking"`
+	Revert     bool        `json:"revert"`
 }
 
 func (c Commit) Sanitize() Commit {
@@ -26,11 +37,16 @@ func (c Commit) Sanitize() Commit {
 	c.Component = cleanString(c.Component)
 	c.Remote = cleanString(c.Remote)
 	c.Repository = cleanString(c.Repository)
+	c.PickedFrom = cleanString(c.PickedFrom)
+	c.Tenant = cleanString(c.Tenant)
+	c.Paths = cleanPaths(c.Paths)
+	c.Branches = cleanBranches(c.Branches)
 
 	return c
 }
 
-func (c Commit) Check() error {
+// Check validates the commit, its content can't be longer than the given number of characters, 0 disabling the limit
+func (c Commit) Check(maxContentLength uint) error {
 	if len(c.Hash) == 0 {
 		return fmt.Errorf("commit's hash is required (e.g. `1a2bc34d`)")
 	}
@@ -43,6 +59,10 @@ func (c Commit) Check() error {
 		return fmt.Errorf("commit's content is required (e.g. `Add README.md`)")
 	}
 
+	if maxContentLength != 0 && uint(utf8.RuneCountInString(c.Content)) > maxContentLength {
+		return fmt.Errorf("commit's content is longer than %d characters", maxContentLength)
+	}
+
 	if c.Date.IsZero() {
 		return fmt.Errorf("commit's date is required (e.g. `1596913344`)")
 	}
@@ -62,7 +82,118 @@ func cleanString(s string) string {
 	return strings.TrimSpace(strings.ToLower(s))
 }
 
+// CleanPath removes surrounding spaces and slashes of a path, case is preserved
+func CleanPath(s string) string {
+	return strings.Trim(strings.TrimPrefix(strings.TrimSpace(s), "./"), "/")
+}
+
+func cleanPaths(paths []string) []string {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	output := make([]string, 0, len(paths))
+	for _, item := range paths {
+		if item = CleanPath(item); len(item) != 0 {
+			output = append(output, item)
+		}
+	}
+
+	return output
+}
+
+// cleanBranches removes surrounding spaces, the `refs/heads/` prefix and duplicates, case is preserved
+func cleanBranches(branches []string) []string {
+	if len(branches) == 0 {
+		return nil
+	}
+
+	output := make([]string, 0, len(branches))
+	seen := make(map[string]struct{}, len(branches))
+
+	for _, item := range branches {
+		item = strings.TrimPrefix(strings.TrimSpace(item), "refs/heads/")
+		if _, ok := seen[item]; ok || len(item) == 0 {
+			continue
+		}
+
+		seen[item] = struct{}{}
+		output = append(output, item)
+	}
+
+	return output
+}
+
 type CommitsList struct {
 	Commits    []Commit `json:"commits"`
 	TotalCount uint     `json:"totalCount"`
 }
+
+// CommitRange is the changelog of commits after a bound, up to another one
+type CommitRange struct {
+	From       RangeBound         `json:"from"`
+	To         RangeBound         `json:"to"`
+	Sections   []ChangelogSection `json:"sections"`
+	TotalCount uint               `json:"totalCount"`
+}
+
+// RangeBound is a bound of a range, resolved from a hash or a deployment
+type RangeBound struct {
+	Date time.Time `json:"date"`
+	Ref  string    `json:"ref"`
+	Hash string    `json:"hash,omitempty"`
+}
+
+// CommitEvent is a saved commit, its ID increases in the order of saving
+type CommitEvent struct {
+	Commit Commit `json:"commit"`
+	ID     uint64 `json:"id"`
+}
+
+// CommitPatch describes a correction of a commit, nil fields are left unchanged
+type CommitPatch struct {
+	Date       *time.Time `json:"date,omitempty"`
+	Type       *string    `json:"type,omitempty"`
+	Component  *string    `json:"component,omitempty"`
+	Content    *string    `json:"content,omitempty"`
+	Remote     *string    `json:"remote,omitempty"`
+	Repository *string    `json:"repository,omitempty"`
+	Breaking   *bool      `json:"breaking,omitempty"`
+	Revert     *bool      `json:"revert,omitempty"`
+}
+
+func (p CommitPatch) Apply(c Commit) Commit {
+	if p.Date != nil {
+		c.Date = *p.Date
+	}
+
+	if p.Type != nil {
+		c.Type = *p.Type
+	}
+
+	if p.Component != nil {
+		c.Component = *p.Component
+	}
+
+	if p.Content != nil {
+		c.Content = *p.Content
+	}
+
+	if p.Remote != nil {
+		c.Remote = *p.Remote
+	}
+
+	if p.Repository != nil {
+		c.Repository = *p.Repository
+	}
+
+	if p.Breaking != nil {
+		c.Breaking = *p.Breaking
+	}
+
+	if p.Revert != nil {
+		c.Revert = *p.Revert
+	}
+
+	return c.Sanitize()
+}
@@ -31,6 +31,18 @@ func TestSanitize(t *testing.T) {
 				Repository: "repository",
 			},
 		},
+		"branches": {
+			Commit{
+				Hash:       "1a2b",
+				PickedFrom: " 5E6F ",
+				Branches:   []string{" main ", "refs/heads/release/1.X", "", "main"},
+			},
+			Commit{
+				Hash:       "1a2b",
+				PickedFrom: "5e6f",
+				Branches:   []string{"main", "release/1.X"},
+			},
+		},
 	}
 
 	for intention, tc := range cases {
@@ -72,6 +84,14 @@ func TestCheck(t *testing.T) {
 			},
 			errors.New("commit's date is required"),
 		},
+		"content too long": {
+			Commit{
+				Hash:    "1ab2c3f4d",
+				Type:    "feat",
+				Content: strings.Repeat("é", DefaultMaxContentLength+1),
+			},
+			errors.New("commit's content is longer than 10000 characters"),
+		},
 		"remote": {
 			Commit{
 				Hash:    "1ab2c3f4d",
@@ -106,7 +126,7 @@ func TestCheck(t *testing.T) {
 
 	for intention, tc := range cases {
 		t.Run(intention, func(t *testing.T) {
-			gotErr := tc.instance.Check()
+			gotErr := tc.instance.Check(DefaultMaxContentLength)
 
 			failed := false
 
@@ -124,3 +144,38 @@ func TestCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestApply(t *testing.T) {
+	fixType := "fix"
+	repository := " ViBiOh/Herodote "
+	breaking := true
+
+	cases := map[string]struct {
+		instance CommitPatch
+		commit   Commit
+		want     Commit
+	}{
+		"empty": {
+			CommitPatch{},
+			Commit{Hash: "1a2bc34d", Type: "fet", Repository: "vibioh/herodote"},
+			Commit{Hash: "1a2bc34d", Type: "fet", Repository: "vibioh/herodote"},
+		},
+		"partial": {
+			CommitPatch{
+				Type:       &fixType,
+				Repository: &repository,
+				Breaking:   &breaking,
+			},
+			Commit{Hash: "1a2bc34d", Type: "fet", Repository: "vibioh/herodot"},
+			Commit{Hash: "1a2bc34d", Type: "fix", Repository: "vibioh/herodote", Breaking: true},
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := tc.instance.Apply(tc.commit); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Apply() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
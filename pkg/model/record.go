@@ -0,0 +1,169 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecordSeparator joins paths and references in a CSV cell
+const RecordSeparator = ";"
+
+// RecordHeader is the header of a CSV of commits
+var RecordHeader = []string{"id", "date", "repository", "remote", "hash", "type", "component", "breaking", "revert", "content", "paths", "refs", "branches", "pickedFrom", "tenant"}
+
+// CommitRecord is a flat commit with a stable schema, for exporting and importing
+type CommitRecord struct {
+	Date       time.Time `json:"date"`
+	Repository string    `json:"repository"`
+	Remote     string    `json:"remote"`
+	Hash       string    `json:"hash"`
+	Type       string    `json:"type"`
+	Component  string    `json:"component"`
+	Content    string    `json:"content"`
+	PickedFrom string    `json:"pickedFrom"`
+	Tenant     string    `json:"tenant"`
+	Paths      []string  `json:"paths"`
+	Refs       []string  `json:"refs"`
+	Branches   []string  `json:"branches"`
+	ID         uint64    `json:"id"`
+	Breaking   bool      `json:"breaking"`
+	Revert     bool      `json:"revert"`
+}
+
+// NewCommitRecord flattens a saved commit, arrays are never nil
+func NewCommitRecord(event CommitEvent) CommitRecord {
+	output := CommitRecord{
+		ID:         event.ID,
+		Date:       event.Commit.Date.UTC(),
+		Repository: event.Commit.Repository,
+		Remote:     event.Commit.Remote,
+		Hash:       event.Commit.Hash,
+		Type:       event.Commit.Type,
+		Component:  event.Commit.Component,
+		Content:    event.Commit.Content,
+		Paths:      event.Commit.Paths,
+		Refs:       ReferenceKeys(event.Commit.References),
+		Branches:   event.Commit.Branches,
+		PickedFrom: event.Commit.PickedFrom,
+		Tenant:     event.Commit.Tenant,
+		Breaking:   event.Commit.Breaking,
+		Revert:     event.Commit.Revert,
+	}
+
+	if output.Paths == nil {
+		output.Paths = []string{}
+	}
+
+	if output.Refs == nil {
+		output.Refs = []string{}
+	}
+
+	if output.Branches == nil {
+		output.Branches = []string{}
+	}
+
+	return output
+}
+
+// Commit returns the commit of the record, its id is dropped
+func (r CommitRecord) Commit() Commit {
+	return Commit{
+		Date:       r.Date,
+		Repository: r.Repository,
+		Remote:     r.Remote,
+		Hash:       r.Hash,
+		Type:       r.Type,
+		Component:  r.Component,
+		Content:    r.Content,
+		Paths:      r.Paths,
+		References: NewReferences(r.Refs),
+		Branches:   r.Branches,
+		PickedFrom: r.PickedFrom,
+		Tenant:     r.Tenant,
+		Breaking:   r.Breaking,
+		Revert:     r.Revert,
+	}
+}
+
+// CSV returns the cells of the record, in the order of RecordHeader
+func (r CommitRecord) CSV() []string {
+	return []string{
+		strconv.FormatUint(r.ID, 10),
+		r.Date.Format(time.RFC3339),
+		r.Repository,
+		r.Remote,
+		r.Hash,
+		r.Type,
+		r.Component,
+		strconv.FormatBool(r.Breaking),
+		strconv.FormatBool(r.Revert),
+		r.Content,
+		strings.Join(r.Paths, RecordSeparator),
+		strings.Join(r.Refs, RecordSeparator),
+		strings.Join(r.Branches, RecordSeparator),
+		r.PickedFrom,
+		r.Tenant,
+	}
+}
+
+// ParseCommitRecord reads the cells of a CSV row, columns are found by their lowercase name in the header
+func ParseCommitRecord(header map[string]int, row []string) (CommitRecord, error) {
+	cell := func(name string) string {
+		if index, ok := header[strings.ToLower(name)]; ok && index < len(row) {
+			return strings.TrimSpace(row[index])
+		}
+
+		return ""
+	}
+
+	var output CommitRecord
+	var err error
+
+	if raw := cell("id"); len(raw) != 0 {
+		if output.ID, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			return output, fmt.Errorf("parse id: %w", err)
+		}
+	}
+
+	if raw := cell("date"); len(raw) != 0 {
+		if output.Date, err = time.Parse(time.RFC3339, raw); err != nil {
+			return output, fmt.Errorf("parse date: %w", err)
+		}
+	}
+
+	if raw := cell("breaking"); len(raw) != 0 {
+		if output.Breaking, err = strconv.ParseBool(raw); err != nil {
+			return output, fmt.Errorf("parse breaking: %w", err)
+		}
+	}
+
+	if raw := cell("revert"); len(raw) != 0 {
+		if output.Revert, err = strconv.ParseBool(raw); err != nil {
+			return output, fmt.Errorf("parse revert: %w", err)
+		}
+	}
+
+	output.Repository = cell("repository")
+	output.Remote = cell("remote")
+	output.Hash = cell("hash")
+	output.Type = cell("type")
+	output.Component = cell("component")
+	output.Content = cell("content")
+	output.Paths = splitRecord(cell("paths"))
+	output.Refs = splitRecord(cell("refs"))
+	output.Branches = splitRecord(cell("branches"))
+	output.PickedFrom = cell("pickedFrom")
+	output.Tenant = cell("tenant")
+
+	return output, nil
+}
+
+func splitRecord(raw string) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	return strings.Split(raw, RecordSeparator)
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCommitRecord(t *testing.T) {
+	record := CommitRecord{
+		ID:         42,
+		Date:       time.Date(2023, 7, 20, 6, 0, 0, 0, time.UTC),
+		Repository: "vibioh/herodote",
+		Remote:     "github.com",
+		Hash:       "1a2bc34d",
+		Type:       "feat",
+		Component:  "api",
+		Content:    "Add import, fixes #12",
+		Paths:      []string{"pkg/importer", "README.md"},
+		Refs:       []string{"#12"},
+		Branches:   []string{"main", "release/1.x"},
+		PickedFrom: "5e6f7a8b",
+		Tenant:     "acme",
+		Breaking:   true,
+	}
+
+	header := make(map[string]int, len(RecordHeader))
+	for index, name := range RecordHeader {
+		header[strings.ToLower(name)] = index
+	}
+
+	cases := map[string]struct {
+		header  map[string]int
+		row     []string
+		want    CommitRecord
+		wantErr bool
+	}{
+		"round trip": {
+			header,
+			record.CSV(),
+			record,
+			false,
+		},
+		"other order": {
+			map[string]int{"hash": 0, "repository": 1, "date": 2},
+			[]string{"1a2bc34d", " vibioh/herodote ", "2023-07-20T06:00:00Z"},
+			CommitRecord{Hash: "1a2bc34d", Repository: "vibioh/herodote", Date: time.Date(2023, 7, 20, 6, 0, 0, 0, time.UTC)},
+			false,
+		},
+		"short row": {
+			header,
+			[]string{"", "", "vibioh/herodote"},
+			CommitRecord{Repository: "vibioh/herodote"},
+			false,
+		},
+		"invalid date": {
+			header,
+			[]string{"1", "yesterday"},
+			CommitRecord{ID: 1},
+			true,
+		},
+		"invalid breaking": {
+			header,
+			[]string{"1", "", "", "", "", "", "", "maybe"},
+			CommitRecord{ID: 1},
+			true,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			got, err := ParseCommitRecord(tc.header, tc.row)
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ParseCommitRecord() error = %v, wantErr %t", err, tc.wantErr)
+			}
+
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ParseCommitRecord() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
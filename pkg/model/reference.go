@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IssuePrefix is the prefix of issues and pull requests references (e.g. `#123`)
+const IssuePrefix = "#"
+
+// Reference is an issue, a pull request or a ticket mentioned by a commit
+type Reference struct {
+	Key string `json:"key"`
+	URL string `json:"url,omitempty"`
+}
+
+// Tracker describes how references of a prefix are linked, `{remote}`, `{repository}`, `{key}` and `{id}` are replaced in the URL
+type Tracker struct {
+	Remote string `json:"remote"`
+	Prefix string `json:"prefix"`
+	URL    string `json:"url"`
+}
+
+func (t Tracker) Sanitize() Tracker {
+	t.Remote = cleanString(t.Remote)
+	t.Prefix = strings.ToUpper(strings.TrimSpace(t.Prefix))
+	t.URL = strings.TrimSpace(t.URL)
+
+	return t
+}
+
+func (t Tracker) Check() error {
+	if len(t.Prefix) == 0 {
+		return fmt.Errorf("tracker's prefix is required (e.g. `#` or `PLAT-`)")
+	}
+
+	if len(t.URL) == 0 {
+		return fmt.Errorf("tracker's url is required (e.g. `https://jira.example.com/browse/{key}`)")
+	}
+
+	return nil
+}
+
+// Matches checks if the tracker links the given reference of a commit
+func (t Tracker) Matches(commit Commit, key string) bool {
+	return (len(t.Remote) == 0 || t.Remote == commit.Remote) && strings.HasPrefix(key, t.Prefix)
+}
+
+// Link returns the URL of the reference of a commit
+func (t Tracker) Link(commit Commit, key string) string {
+	return strings.NewReplacer(
+		"{remote}", commit.Remote,
+		"{repository}", commit.Repository,
+		"{key}", key,
+		"{id}", strings.TrimPrefix(key, t.Prefix),
+	).Replace(t.URL)
+}
+
+// ReferenceKeys returns the keys of references
+func ReferenceKeys(references []Reference) []string {
+	output := make([]string, len(references))
+
+	for index, reference := range references {
+		output[index] = reference.Key
+	}
+
+	return output
+}
+
+// NewReferences creates references from their keys
+func NewReferences(keys []string) []Reference {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	output := make([]Reference, len(keys))
+
+	for index, key := range keys {
+		output[index] = Reference{Key: key}
+	}
+
+	return output
+}
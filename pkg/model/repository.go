@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// RepositoryAlias redirects a former repository's name, after a rename or a transfer, to its current name
+type RepositoryAlias struct {
+	Alias      string `json:"alias"`
+	Repository string `json:"repository"`
+}
+
+func (r RepositoryAlias) Sanitize() RepositoryAlias {
+	r.Alias = cleanString(r.Alias)
+	r.Repository = cleanString(r.Repository)
+
+	return r
+}
+
+func (r RepositoryAlias) Check() error {
+	if len(r.Alias) == 0 {
+		return fmt.Errorf("alias is required (e.g. `vibioh/herodot`)")
+	}
+
+	if len(r.Repository) == 0 {
+		return fmt.Errorf("repository is required (e.g. `vibioh/herodote`)")
+	}
+
+	if r.Alias == r.Repository {
+		return fmt.Errorf("alias and repository must be different")
+	}
+
+	return nil
+}
+
+type RenamedRepository struct {
+	RepositoryAlias
+	Count uint64 `json:"count"`
+}
+
+// PrivateRepository is only visible to the admin and to readers of its tenant
+type PrivateRepository struct {
+	Repository string `json:"repository"`
+	Tenant     string `json:"tenant"`
+}
+
+func (p PrivateRepository) Sanitize() PrivateRepository {
+	p.Repository = cleanString(p.Repository)
+	p.Tenant = cleanString(p.Tenant)
+
+	return p
+}
+
+func (p PrivateRepository) Check() error {
+	if len(p.Repository) == 0 {
+		return fmt.Errorf("repository is required (e.g. `acme/billing`)")
+	}
+
+	if len(p.Tenant) == 0 {
+		return fmt.Errorf("tenant is required (e.g. `acme`)")
+	}
+
+	if IsVisibilityPattern(p.Tenant) {
+		return fmt.Errorf("tenant can't contain a `/`")
+	}
+
+	return nil
+}
+
+// PrivateRepositories maps private repositories to their tenant
+type PrivateRepositories map[string]string
+
+func NewPrivateRepositories(list []PrivateRepository) PrivateRepositories {
+	output := make(PrivateRepositories, len(list))
+	for _, item := range list {
+		output[item.Repository] = item.Tenant
+	}
+
+	return output
+}
+
+// Visible checks if the repository is visible to a reader with the given visibility, see Token.Visibility
+func (p PrivateRepositories) Visible(visibility []string, repository string) bool {
+	tenant, ok := p[repository]
+	if !ok || visibility == nil {
+		return true
+	}
+
+	for _, item := range visibility {
+		if item == tenant {
+			return true
+		}
+
+		if IsVisibilityPattern(item) {
+			if ok, err := path.Match(item, repository); err == nil && ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsVisibilityPattern checks if an item of a visibility is a repository pattern (e.g. `acme/*`) rather than a tenant
+func IsVisibilityPattern(item string) bool {
+	return strings.Contains(item, "/")
+}
+
+// SplitVisibility separates tenants and repository patterns of a visibility, patterns are empty rather than nil unless the visibility is
+func SplitVisibility(visibility []string) (tenants, patterns []string) {
+	if visibility == nil {
+		return nil, nil
+	}
+
+	tenants = make([]string, 0, len(visibility))
+	patterns = make([]string, 0)
+
+	for _, item := range visibility {
+		if IsVisibilityPattern(item) {
+			patterns = append(patterns, item)
+		} else {
+			tenants = append(tenants, item)
+		}
+	}
+
+	return tenants, patterns
+}
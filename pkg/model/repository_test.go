@@ -0,0 +1,105 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRepositoryAliasCheck(t *testing.T) {
+	cases := map[string]struct {
+		instance RepositoryAlias
+		wantErr  error
+	}{
+		"empty": {
+			RepositoryAlias{},
+			errors.New("alias is required"),
+		},
+		"repository": {
+			RepositoryAlias{Alias: "vibioh/herodot"},
+			errors.New("repository is required"),
+		},
+		"same": {
+			RepositoryAlias{Alias: "vibioh/herodote", Repository: "vibioh/herodote"},
+			errors.New("alias and repository must be different"),
+		},
+		"valid": {
+			RepositoryAlias{Alias: "vibioh/herodot", Repository: "vibioh/herodote"},
+			nil,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			gotErr := tc.instance.Check()
+
+			failed := false
+
+			if tc.wantErr == nil && gotErr != nil {
+				failed = true
+			} else if tc.wantErr != nil && gotErr == nil {
+				failed = true
+			} else if tc.wantErr != nil && !strings.Contains(gotErr.Error(), tc.wantErr.Error()) {
+				failed = true
+			}
+
+			if failed {
+				t.Errorf("Check() = `%s`, want `%s`", gotErr, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrivateRepositoriesVisible(t *testing.T) {
+	privates := NewPrivateRepositories([]PrivateRepository{{Repository: "acme/billing", Tenant: "acme"}})
+
+	cases := map[string]struct {
+		visibility []string
+		repository string
+		want       bool
+	}{
+		"public": {
+			[]string{""},
+			"vibioh/herodote",
+			true,
+		},
+		"anonymous": {
+			Token{}.Visibility(),
+			"acme/billing",
+			false,
+		},
+		"other tenant": {
+			Token{Tenant: "globex"}.Visibility(),
+			"acme/billing",
+			false,
+		},
+		"tenant": {
+			Token{Tenant: "acme"}.Visibility(),
+			"acme/billing",
+			true,
+		},
+		"admin": {
+			AdminToken.Visibility(),
+			"acme/billing",
+			true,
+		},
+		"pattern": {
+			[]string{"globex", "acme/*"},
+			"acme/billing",
+			true,
+		},
+		"other pattern": {
+			[]string{"acme/web*"},
+			"acme/billing",
+			false,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := privates.Visible(tc.visibility, tc.repository); got != tc.want {
+				t.Errorf("Visible() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
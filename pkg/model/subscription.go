@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+	"net/mail"
+	"time"
+)
+
+const (
+	DailyFrequency  = "daily"
+	WeeklyFrequency = "weekly"
+)
+
+// Subscription sends periodically by email the changelog of commits matching its filter
+type Subscription struct {
+	CreationDate time.Time    `json:"creationDate"`
+	LastSent     *time.Time   `json:"lastSent,omitempty"`
+	Owner        string       `json:"owner"`
+	Tenant       string       `json:"tenant,omitempty"`
+	Email        string       `json:"email"`
+	Frequency    string       `json:"frequency"`
+	Filter       CommitFilter `json:"filter"`
+	ID           uint64       `json:"id"`
+}
+
+func (s Subscription) Sanitize() Subscription {
+	s.Email = cleanString(s.Email)
+	s.Frequency = cleanString(s.Frequency)
+	s.Filter = s.Filter.Sanitize()
+
+	if len(s.Frequency) == 0 {
+		s.Frequency = WeeklyFrequency
+	}
+
+	return s
+}
+
+func (s Subscription) Check() error {
+	if address, err := mail.ParseAddress(s.Email); err != nil || address.Address != s.Email {
+		return fmt.Errorf("subscription's email is invalid (e.g. `changelog@example.com`)")
+	}
+
+	if s.Frequency != DailyFrequency && s.Frequency != WeeklyFrequency {
+		return fmt.Errorf("subscription's frequency must be `%s` or `%s`", DailyFrequency, WeeklyFrequency)
+	}
+
+	return s.Filter.Check()
+}
+
+// Period returns the duration between two emails
+func (s Subscription) Period() time.Duration {
+	if s.Frequency == DailyFrequency {
+		return time.Hour * 24
+	}
+
+	return time.Hour * 24 * 7
+}
+
+// ChangelogSection groups commits of types sharing a section
+type ChangelogSection struct {
+	Title      string   `json:"title"`
+	Commits    []Commit `json:"commits"`
+	Production bool     `json:"production"`
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+const allRepositoriesPattern = "*"
+
+type Token struct {
+	CreationDate time.Time  `json:"creationDate"`
+	LastUsed     *time.Time `json:"lastUsed,omitempty"`
+	Name         string     `json:"name"`
+	Tenant       string     `json:"tenant,omitempty"`
+	Patterns     []string   `json:"patterns"`
+	Admin        bool       `json:"-"`
+}
+
+// AdminToken is the identity of the holder of the global HTTP secret
+var AdminToken = Token{
+	Name:     "admin",
+	Patterns: []string{allRepositoriesPattern},
+	Admin:    true,
+}
+
+func (t Token) Sanitize() Token {
+	t.Name = strings.TrimSpace(t.Name)
+	t.Tenant = cleanString(t.Tenant)
+
+	patterns := make([]string, 0, len(t.Patterns))
+	for _, pattern := range t.Patterns {
+		if pattern = cleanString(pattern); len(pattern) != 0 {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	t.Patterns = patterns
+
+	return t
+}
+
+func (t Token) Check() error {
+	if len(t.Name) == 0 {
+		return fmt.Errorf("token's name is required (e.g. `ci-vibioh`)")
+	}
+
+	if len(t.Patterns) == 0 {
+		return fmt.Errorf("token's patterns are required (e.g. `vibioh/*`)")
+	}
+
+	if IsVisibilityPattern(t.Tenant) {
+		return fmt.Errorf("token's tenant can't contain a `/`")
+	}
+
+	for _, pattern := range t.Patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("token's pattern `%s` is invalid: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// Allows checks if the token is allowed to write to the given repository
+func (t Token) Allows(repository string) bool {
+	repository = cleanString(repository)
+
+	for _, pattern := range t.Patterns {
+		if pattern == allRepositoriesPattern {
+			return true
+		}
+
+		if ok, err := path.Match(pattern, repository); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Visibility lists the tenants whose private repositories are visible with the token, nil for every tenant.
+// Without tenant, only public repositories are visible. Items with a `/` are repository patterns, see SplitVisibility.
+func (t Token) Visibility() []string {
+	if t.Admin {
+		return nil
+	}
+
+	return []string{t.Tenant}
+}
+
+// HashToken returns the representation of a token secret stored at rest
+func HashToken(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTokenCheck(t *testing.T) {
+	cases := map[string]struct {
+		instance Token
+		wantErr  error
+	}{
+		"empty": {
+			Token{},
+			errors.New("token's name is required"),
+		},
+		"patterns": {
+			Token{
+				Name: "ci",
+			},
+			errors.New("token's patterns are required"),
+		},
+		"invalid pattern": {
+			Token{
+				Name:     "ci",
+				Patterns: []string{"vibioh/[*"},
+			},
+			errors.New("token's pattern `vibioh/[*` is invalid"),
+		},
+		"valid": {
+			Token{
+				Name:     "ci",
+				Patterns: []string{"vibioh/*"},
+			},
+			nil,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			gotErr := tc.instance.Check()
+
+			failed := false
+
+			if tc.wantErr == nil && gotErr != nil {
+				failed = true
+			} else if tc.wantErr != nil && gotErr == nil {
+				failed = true
+			} else if tc.wantErr != nil && !strings.Contains(gotErr.Error(), tc.wantErr.Error()) {
+				failed = true
+			}
+
+			if failed {
+				t.Errorf("Check() = `%s`, want `%s`", gotErr, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+	cases := map[string]struct {
+		instance   Token
+		repository string
+		want       bool
+	}{
+		"none": {
+			Token{},
+			"vibioh/herodote",
+			false,
+		},
+		"exact": {
+			Token{Patterns: []string{"vibioh/herodote"}},
+			"ViBiOh/herodote",
+			true,
+		},
+		"wildcard": {
+			Token{Patterns: []string{"vibioh/*"}},
+			"vibioh/herodote",
+			true,
+		},
+		"other owner": {
+			Token{Patterns: []string{"vibioh/*"}},
+			"golang/go",
+			false,
+		},
+		"all": {
+			AdminToken,
+			"golang/go",
+			true,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := tc.instance.Allows(tc.repository); got != tc.want {
+				t.Errorf("Allows() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
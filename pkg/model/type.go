@@ -0,0 +1,38 @@
+package model
+
+import (
+	"fmt"
+)
+
+// Type describes a conventional commit type
+type Type struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Color       string   `json:"color"`
+	Section     string   `json:"section"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Production  bool     `json:"production"`
+}
+
+func (t Type) Sanitize() Type {
+	t.Name = cleanString(t.Name)
+
+	aliases := make([]string, 0, len(t.Aliases))
+	for _, alias := range t.Aliases {
+		if alias = cleanString(alias); len(alias) != 0 {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	t.Aliases = aliases
+
+	return t
+}
+
+func (t Type) Check() error {
+	if len(t.Name) == 0 {
+		return fmt.Errorf("type's name is required (e.g. `feat`)")
+	}
+
+	return nil
+}
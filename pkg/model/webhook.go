@@ -0,0 +1,154 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// CommitFilter selects commits with the vocabulary of search filters, an empty field matches every commit
+type CommitFilter struct {
+	Repositories []string `json:"repository,omitempty"`
+	Types        []string `json:"type,omitempty"`
+	Components   []string `json:"component,omitempty"`
+	Paths        []string `json:"path,omitempty"`
+	Refs         []string `json:"ref,omitempty"`
+	Breaking     bool     `json:"breaking,omitempty"`
+}
+
+func (f CommitFilter) Sanitize() CommitFilter {
+	f.Repositories = cleanStrings(f.Repositories, cleanString)
+	f.Types = cleanStrings(f.Types, cleanString)
+	f.Components = cleanStrings(f.Components, cleanString)
+	f.Paths = cleanStrings(f.Paths, CleanPath)
+	f.Refs = cleanStrings(f.Refs, func(s string) string {
+		return strings.ToUpper(strings.TrimSpace(s))
+	})
+
+	return f
+}
+
+func (f CommitFilter) Check() error {
+	for _, repository := range f.Repositories {
+		if _, err := path.Match(repository, ""); err != nil {
+			return fmt.Errorf("filter's repository `%s` is invalid: %w", repository, err)
+		}
+	}
+
+	return nil
+}
+
+// Matches checks if the commit matches the filter, repositories are patterns (e.g. `vibioh/*`)
+func (f CommitFilter) Matches(commit Commit) bool {
+	if f.Breaking && !commit.Breaking {
+		return false
+	}
+
+	if len(f.Repositories) != 0 && !matchesAny(f.Repositories, func(pattern string) bool {
+		matched, _ := path.Match(pattern, commit.Repository)
+		return matched
+	}) {
+		return false
+	}
+
+	if len(f.Types) != 0 && !matchesAny(f.Types, func(value string) bool { return value == commit.Type }) {
+		return false
+	}
+
+	if len(f.Components) != 0 && !matchesAny(f.Components, func(value string) bool { return value == commit.Component }) {
+		return false
+	}
+
+	if len(f.Paths) != 0 && !matchesAny(f.Paths, func(value string) bool {
+		return matchesAny(commit.Paths, func(changed string) bool {
+			return changed == value || strings.HasPrefix(changed, value+"/")
+		})
+	}) {
+		return false
+	}
+
+	if len(f.Refs) != 0 && !matchesAny(f.Refs, func(value string) bool {
+		return matchesAny(ReferenceKeys(commit.References), func(key string) bool { return key == value })
+	}) {
+		return false
+	}
+
+	return true
+}
+
+func matchesAny(values []string, predicate func(string) bool) bool {
+	for _, value := range values {
+		if predicate(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func cleanStrings(values []string, cleaner func(string) string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	output := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = cleaner(value); len(value) != 0 {
+			output = append(output, value)
+		}
+	}
+
+	return output
+}
+
+// Webhook is a subscription notified by a signed POST for each saved commit matching its filter
+type Webhook struct {
+	CreationDate time.Time    `json:"creationDate"`
+	Name         string       `json:"name"`
+	URL          string       `json:"url"`
+	Secret       string       `json:"secret,omitempty"`
+	Filter       CommitFilter `json:"filter"`
+}
+
+func (w Webhook) Sanitize() Webhook {
+	w.Name = cleanString(w.Name)
+	w.URL = strings.TrimSpace(w.URL)
+	w.Secret = strings.TrimSpace(w.Secret)
+	w.Filter = w.Filter.Sanitize()
+
+	// the signature covers the path, that is never empty on the receiver's side
+	if parsed, err := url.Parse(w.URL); err == nil && len(parsed.Path) == 0 {
+		parsed.Path = "/"
+		w.URL = parsed.String()
+	}
+
+	return w
+}
+
+func (w Webhook) Check() error {
+	if len(w.Name) == 0 {
+		return fmt.Errorf("webhook's name is required (e.g. `release-train`)")
+	}
+
+	if strings.Contains(w.Name, "/") {
+		return fmt.Errorf("webhook's name must not contain `/`")
+	}
+
+	if parsed, err := url.Parse(w.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || len(parsed.Host) == 0 {
+		return fmt.Errorf("webhook's url must be an absolute http(s) URL (e.g. `https://example.com/hooks/herodote`)")
+	}
+
+	return w.Filter.Check()
+}
+
+// WebhookFailure is a delivery that failed after every attempt, kept as a dead letter
+type WebhookFailure struct {
+	CreationDate time.Time       `json:"creationDate"`
+	Webhook      string          `json:"webhook"`
+	Error        string          `json:"error"`
+	Payload      json.RawMessage `json:"payload"`
+	Attempts     uint            `json:"attempts"`
+}
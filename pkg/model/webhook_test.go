@@ -0,0 +1,60 @@
+package model
+
+import "testing"
+
+func TestCommitFilterMatches(t *testing.T) {
+	commit := Commit{
+		Repository: "vibioh/herodote",
+		Type:       "feat",
+		Component:  "api",
+		Paths:      []string{"services/billing/main.go"},
+		References: []Reference{{Key: "PLAT-789"}},
+		Breaking:   true,
+	}
+
+	cases := map[string]struct {
+		instance CommitFilter
+		want     bool
+	}{
+		"empty": {
+			CommitFilter{},
+			true,
+		},
+		"repository pattern": {
+			CommitFilter{Repositories: []string{"vibioh/*"}, Types: []string{"feat"}},
+			true,
+		},
+		"other repository": {
+			CommitFilter{Repositories: []string{"golang/*"}},
+			false,
+		},
+		"other type": {
+			CommitFilter{Types: []string{"fix", "perf"}},
+			false,
+		},
+		"breaking": {
+			CommitFilter{Breaking: true, Components: []string{"api"}},
+			true,
+		},
+		"path": {
+			CommitFilter{Paths: []string{"services/billing"}},
+			true,
+		},
+		"path prefix": {
+			CommitFilter{Paths: []string{"services/bill"}},
+			false,
+		},
+		"ref": {
+			CommitFilter{Refs: []string{"PLAT-789"}},
+			true,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := tc.instance.Matches(commit); got != tc.want {
+				t.Errorf("Matches() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
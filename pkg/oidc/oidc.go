@@ -0,0 +1,262 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/httputils/v4/pkg/httperror"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+)
+
+const (
+	// Path is the prefix of the login routes, handled by Handler
+	Path = "/auth"
+
+	loginPath    = Path + "/login"
+	callbackPath = Path + "/callback"
+	logoutPath   = Path + "/logout"
+
+	sessionCookie = "herodote_session"
+	flowCookie    = "herodote_oidc"
+	flowDuration  = time.Minute * 10
+
+	// allRepositories is the mapping of a group seeing every repository, like the admin secret
+	allRepositories = "*"
+)
+
+type App struct {
+	provider    *provider
+	groups      map[string][]string
+	clientID    string
+	secret      string
+	redirectURL string
+	groupsClaim string
+	scopes      []string
+	cookieKey   []byte
+	duration    time.Duration
+	secure      bool
+}
+
+type Config struct {
+	issuer       *string
+	clientID     *string
+	clientSecret *string
+	redirectURL  *string
+	scopes       *[]string
+	groupsClaim  *string
+	groups       *[]string
+	cookieSecret *string
+	duration     *time.Duration
+}
+
+func Flags(fs *flag.FlagSet, prefix string) Config {
+	return Config{
+		issuer:       flags.New("Issuer", "OpenID Connect issuer URL, enables the login of the web UI").Prefix(prefix).DocPrefix("oidc").String(fs, "", nil),
+		clientID:     flags.New("ClientID", "OpenID Connect client ID").Prefix(prefix).DocPrefix("oidc").String(fs, "", nil),
+		clientSecret: flags.New("ClientSecret", "OpenID Connect client secret").Prefix(prefix).DocPrefix("oidc").String(fs, "", nil),
+		redirectURL:  flags.New("RedirectURL", "Public URL of the callback, ending with "+callbackPath).Prefix(prefix).DocPrefix("oidc").String(fs, "https://herodote.vibioh.fr"+callbackPath, nil),
+		scopes:       flags.New("Scopes", "Requested scopes").Prefix(prefix).DocPrefix("oidc").StringSlice(fs, []string{"openid", "profile", "email"}, nil),
+		groupsClaim:  flags.New("GroupsClaim", "Claim of the ID token listing groups of the user").Prefix(prefix).DocPrefix("oidc").String(fs, "groups", nil),
+		groups:       flags.New("Groups", "Mapping of a group to a tenant, a repository pattern or * for every repository, as group=value").Prefix(prefix).DocPrefix("oidc").StringSlice(fs, nil, nil),
+		cookieSecret: flags.New("CookieSecret", "Secret signing the session cookies").Prefix(prefix).DocPrefix("oidc").String(fs, "", nil),
+		duration:     flags.New("SessionDuration", "Duration of a session").Prefix(prefix).DocPrefix("oidc").Duration(fs, time.Hour*12, nil),
+	}
+}
+
+// New creates the app, it's disabled without issuer
+func New(config Config) (App, error) {
+	issuer := strings.TrimSuffix(strings.TrimSpace(*config.issuer), "/")
+	if len(issuer) == 0 {
+		return App{}, nil
+	}
+
+	if len(*config.clientID) == 0 {
+		return App{}, errors.New("client ID is required")
+	}
+
+	if len(*config.cookieSecret) == 0 {
+		return App{}, errors.New("cookie secret is required")
+	}
+
+	redirectURL, err := url.Parse(*config.redirectURL)
+	if err != nil {
+		return App{}, fmt.Errorf("parse redirect URL: %w", err)
+	}
+
+	groups, err := parseGroups(*config.groups)
+	if err != nil {
+		return App{}, err
+	}
+
+	return App{
+		provider:    &provider{issuer: issuer},
+		groups:      groups,
+		clientID:    *config.clientID,
+		secret:      *config.clientSecret,
+		redirectURL: redirectURL.String(),
+		groupsClaim: *config.groupsClaim,
+		scopes:      *config.scopes,
+		cookieKey:   []byte(*config.cookieSecret),
+		duration:    *config.duration,
+		secure:      redirectURL.Scheme == "https",
+	}, nil
+}
+
+func parseGroups(mappings []string) (map[string][]string, error) {
+	groups := make(map[string][]string)
+
+	for _, mapping := range mappings {
+		group, value, ok := strings.Cut(mapping, "=")
+		group = strings.TrimSpace(group)
+		value = strings.ToLower(strings.TrimSpace(value))
+
+		if !ok || len(group) == 0 || len(value) == 0 {
+			return nil, fmt.Errorf("group mapping `%s` is not in the form `group=value`", mapping)
+		}
+
+		groups[group] = append(groups[group], value)
+	}
+
+	return groups, nil
+}
+
+func (a App) Enabled() bool {
+	return a.provider != nil
+}
+
+// Handler logs users in and out of the web UI
+func (a App) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		switch r.URL.Path {
+		case loginPath:
+			a.handleLogin(w, r)
+		case callbackPath:
+			a.handleCallback(w, r)
+		case logoutPath:
+			a.clearCookie(w, sessionCookie, "/")
+			http.Redirect(w, r, "/", http.StatusFound)
+		default:
+			httperror.NotFound(w)
+		}
+	})
+}
+
+// flow is kept in a cookie between the login and the callback
+type flow struct {
+	Expires  time.Time `json:"expires"`
+	State    string    `json:"state"`
+	Nonce    string    `json:"nonce"`
+	Verifier string    `json:"verifier"`
+	Redirect string    `json:"redirect"`
+}
+
+func (a App) handleLogin(w http.ResponseWriter, r *http.Request) {
+	authorizationEndpoint, err := a.provider.authorizationEndpoint(r.Context())
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	current := flow{
+		Expires:  time.Now().Add(flowDuration),
+		Redirect: localRedirect(r.URL.Query().Get("redirect")),
+	}
+
+	for _, value := range []*string{&current.State, &current.Nonce, &current.Verifier} {
+		if *value, err = randomString(); err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+	}
+
+	if err = a.setCookie(w, flowCookie, Path, current, current.Expires); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(current.Verifier))
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", a.clientID)
+	params.Set("redirect_uri", a.redirectURL)
+	params.Set("scope", strings.Join(a.scopes, " "))
+	params.Set("state", current.State)
+	params.Set("nonce", current.Nonce)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+
+	http.Redirect(w, r, authorizationEndpoint+"?"+params.Encode(), http.StatusFound)
+}
+
+func (a App) handleCallback(w http.ResponseWriter, r *http.Request) {
+	var current flow
+	if err := a.readCookie(r, flowCookie, &current); err != nil || time.Now().After(current.Expires) {
+		httperror.BadRequest(w, errors.New("login has expired, try again"))
+		return
+	}
+
+	a.clearCookie(w, flowCookie, Path)
+
+	params := r.URL.Query()
+
+	if params.Get("state") != current.State {
+		httperror.BadRequest(w, errors.New("invalid state"))
+		return
+	}
+
+	if providerErr := params.Get("error"); len(providerErr) != 0 {
+		httperror.Forbidden(w)
+		logger.Warn("login refused by the provider: %s %s", providerErr, params.Get("error_description"))
+		return
+	}
+
+	claims, err := a.exchange(r.Context(), params.Get("code"), current.Verifier, current.Nonce)
+	if err != nil {
+		logger.Error("exchange code: %s", err)
+		httperror.Unauthorized(w, errors.New("login failed"))
+		return
+	}
+
+	session := a.newSession(claims, time.Now())
+
+	if err = a.setCookie(w, sessionCookie, "/", session, session.Expires); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	http.Redirect(w, r, current.Redirect, http.StatusFound)
+}
+
+// localRedirect only allows redirections to pages of the app
+func localRedirect(redirect string) string {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+		return "/"
+	}
+
+	return redirect
+}
+
+func randomString() (string, error) {
+	raw := make([]byte, 32)
+
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate random: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
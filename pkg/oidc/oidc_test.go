@@ -0,0 +1,381 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockIssuer is a local OpenID Connect provider, it signs the ID token with the claims it's given
+type mockIssuer struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	claims   map[string]any
+	verifier string
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := &mockIssuer{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 issuer.server.URL,
+			"authorization_endpoint": issuer.server.URL + "/authorize",
+			"token_endpoint":         issuer.server.URL + "/token",
+			"jwks_uri":               issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kid": "mock",
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if clientID, secret, ok := r.BasicAuth(); !ok || clientID != "herodote" || secret != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.FormValue("code") != "code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		issuer.verifier = r.FormValue("code_verifier")
+		writeJSON(w, map[string]string{"id_token": issuer.sign(t, issuer.claims)})
+	})
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (m *mockIssuer) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "mock"})
+	payload, _ := json.Marshal(claims)
+
+	content := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(content))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, m.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return content + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func newTestApp(t *testing.T, issuer string) App {
+	t.Helper()
+
+	fs := flag.NewFlagSet("oidc", flag.ContinueOnError)
+	config := Flags(fs, "")
+
+	if err := fs.Parse([]string{
+		"-issuer", issuer,
+		"-clientID", "herodote",
+		"-clientSecret", "client-secret",
+		"-redirectURL", "http://localhost:1080" + callbackPath,
+		"-cookieSecret", "cookie-secret",
+		"-groups", "acme-devs=acme",
+		"-groups", "globex-billing=globex/billing*",
+		"-groups", "herodote-admins=*",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	app, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return app
+}
+
+func TestLogin(t *testing.T) {
+	issuer := newMockIssuer(t)
+
+	cases := map[string]struct {
+		claims         func(nonce string) map[string]any
+		state          string
+		wantStatus     int
+		wantVisibility []string
+		wantName       string
+		wantAll        bool
+	}{
+		"tenant and pattern": {
+			func(nonce string) map[string]any {
+				return map[string]any{"iss": issuer.server.URL, "aud": "herodote", "sub": "42", "nonce": nonce, "exp": time.Now().Add(time.Hour).Unix(), "email": "jane@acme.com", "groups": []string{"acme-devs", "globex-billing", "unknown"}}
+			},
+			"",
+			http.StatusFound,
+			[]string{"acme", "globex/billing*"},
+			"jane@acme.com",
+			false,
+		},
+		"every repository": {
+			func(nonce string) map[string]any {
+				return map[string]any{"iss": issuer.server.URL, "aud": []string{"other", "herodote"}, "sub": "1", "nonce": nonce, "exp": time.Now().Add(time.Hour).Unix(), "groups": "herodote-admins"}
+			},
+			"",
+			http.StatusFound,
+			nil,
+			"1",
+			true,
+		},
+		"without group": {
+			func(nonce string) map[string]any {
+				return map[string]any{"iss": issuer.server.URL, "aud": "herodote", "sub": "7", "nonce": nonce, "exp": time.Now().Add(time.Hour).Unix(), "name": "John"}
+			},
+			"",
+			http.StatusFound,
+			[]string{""},
+			"John",
+			false,
+		},
+		"invalid state": {
+			func(nonce string) map[string]any {
+				return map[string]any{"iss": issuer.server.URL, "aud": "herodote", "sub": "42", "nonce": nonce, "exp": time.Now().Add(time.Hour).Unix()}
+			},
+			"forged",
+			http.StatusBadRequest,
+			nil,
+			"",
+			false,
+		},
+		"replayed nonce": {
+			func(string) map[string]any {
+				return map[string]any{"iss": issuer.server.URL, "aud": "herodote", "sub": "42", "nonce": "replayed", "exp": time.Now().Add(time.Hour).Unix()}
+			},
+			"",
+			http.StatusUnauthorized,
+			nil,
+			"",
+			false,
+		},
+		"other audience": {
+			func(nonce string) map[string]any {
+				return map[string]any{"iss": issuer.server.URL, "aud": "other", "sub": "42", "nonce": nonce, "exp": time.Now().Add(time.Hour).Unix()}
+			},
+			"",
+			http.StatusUnauthorized,
+			nil,
+			"",
+			false,
+		},
+		"expired": {
+			func(nonce string) map[string]any {
+				return map[string]any{"iss": issuer.server.URL, "aud": "herodote", "sub": "42", "nonce": nonce, "exp": time.Now().Add(-time.Hour).Unix()}
+			},
+			"",
+			http.StatusUnauthorized,
+			nil,
+			"",
+			false,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			app := newTestApp(t, issuer.server.URL)
+
+			writer := httptest.NewRecorder()
+			app.Handler().ServeHTTP(writer, httptest.NewRequest(http.MethodGet, loginPath+"?redirect=/?repository=acme/billing", nil))
+
+			if writer.Code != http.StatusFound {
+				t.Fatalf("login = %d, want %d", writer.Code, http.StatusFound)
+			}
+
+			authorization, err := url.Parse(writer.Header().Get("Location"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			params := authorization.Query()
+			issuer.claims = tc.claims(params.Get("nonce"))
+
+			state := params.Get("state")
+			if len(tc.state) != 0 {
+				state = tc.state
+			}
+
+			callback := httptest.NewRequest(http.MethodGet, callbackPath+"?code=code&state="+state, nil)
+			for _, cookie := range writer.Result().Cookies() {
+				callback.AddCookie(cookie)
+			}
+
+			writer = httptest.NewRecorder()
+			app.Handler().ServeHTTP(writer, callback)
+
+			if writer.Code != tc.wantStatus {
+				t.Fatalf("callback = %d, want %d: %s", writer.Code, tc.wantStatus, writer.Body.String())
+			}
+
+			if tc.wantStatus != http.StatusFound {
+				return
+			}
+
+			if got := writer.Header().Get("Location"); got != "/?repository=acme/billing" {
+				t.Errorf("callback redirects to `%s`", got)
+			}
+
+			challenge := sha256.Sum256([]byte(issuer.verifier))
+			if got := base64.RawURLEncoding.EncodeToString(challenge[:]); got != params.Get("code_challenge") {
+				t.Errorf("code verifier doesn't match the challenge")
+			}
+
+			page := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, cookie := range writer.Result().Cookies() {
+				page.AddCookie(cookie)
+			}
+
+			session, ok := app.Session(page)
+			if !ok {
+				t.Fatal("Session() = false, want true")
+			}
+
+			if strings.Join(session.Visibility, ",") != strings.Join(tc.wantVisibility, ",") || (session.Visibility == nil) != (tc.wantVisibility == nil) {
+				t.Errorf("Session() visibility = %#v, want %#v", session.Visibility, tc.wantVisibility)
+			}
+
+			if session.All != tc.wantAll {
+				t.Errorf("Session() all = %t, want %t", session.All, tc.wantAll)
+			}
+
+			if session.Name != tc.wantName {
+				t.Errorf("Session() name = `%s`, want `%s`", session.Name, tc.wantName)
+			}
+		})
+	}
+}
+
+func TestSession(t *testing.T) {
+	app := newTestApp(t, "http://localhost:1081")
+
+	cases := map[string]struct {
+		session Session
+		tamper  bool
+		replay  bool
+		want    bool
+	}{
+		"valid": {
+			Session{Subject: "42", Expires: time.Now().Add(time.Hour), Visibility: []string{"acme"}},
+			false,
+			false,
+			true,
+		},
+		"expired": {
+			Session{Subject: "42", Expires: time.Now().Add(-time.Hour)},
+			false,
+			false,
+			false,
+		},
+		"tampered": {
+			Session{Subject: "42", Expires: time.Now().Add(time.Hour), Visibility: []string{"acme"}},
+			true,
+			false,
+			false,
+		},
+		"flow cookie": {
+			Session{Expires: time.Now().Add(time.Hour)},
+			false,
+			true,
+			false,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			writer := httptest.NewRecorder()
+
+			var err error
+			if tc.replay {
+				// a flow cookie is given to anonymous visitors by the login
+				err = app.setCookie(writer, flowCookie, Path, flow{Expires: tc.session.Expires, State: "state"}, tc.session.Expires)
+			} else {
+				err = app.setCookie(writer, sessionCookie, "/", tc.session, tc.session.Expires)
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cookie := writer.Result().Cookies()[0]
+			cookie.Name = sessionCookie
+			if tc.tamper {
+				payload, _ := json.Marshal(Session{Subject: "42", Expires: tc.session.Expires})
+				_, signature, _ := strings.Cut(cookie.Value, ".")
+				cookie.Value = base64.RawURLEncoding.EncodeToString(payload) + "." + signature
+			}
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.AddCookie(cookie)
+
+			if _, got := app.Session(request); got != tc.want {
+				t.Errorf("Session() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLocalRedirect(t *testing.T) {
+	cases := map[string]struct {
+		redirect string
+		want     string
+	}{
+		"empty": {
+			"",
+			"/",
+		},
+		"page": {
+			"/?repository=vibioh/herodote",
+			"/?repository=vibioh/herodote",
+		},
+		"absolute": {
+			"https://evil.example.com",
+			"/",
+		},
+		"protocol relative": {
+			"//evil.example.com",
+			"/",
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := localRedirect(tc.redirect); got != tc.want {
+				t.Errorf("localRedirect() = `%s`, want `%s`", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package oidc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+var errInvalidCookie = errors.New("invalid cookie")
+
+// Session is a user logged in the web UI
+type Session struct {
+	Expires time.Time `json:"expires"`
+	Subject string    `json:"sub"`
+	Name    string    `json:"name"`
+	// Visibility lists tenants and repository patterns of the groups of the user, ignored if every repository is visible
+	Visibility []string `json:"visibility"`
+	All        bool     `json:"all"`
+}
+
+type sessionKey struct{}
+
+func WithSession(ctx context.Context, session Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// FromContext returns the session of the user of the request, if any
+func FromContext(ctx context.Context) (Session, bool) {
+	session, ok := ctx.Value(sessionKey{}).(Session)
+	return session, ok
+}
+
+// Session reads the session cookie of the request, it's ignored if invalid or expired
+func (a App) Session(r *http.Request) (Session, bool) {
+	if !a.Enabled() {
+		return Session{}, false
+	}
+
+	var session Session
+	if err := a.readCookie(r, sessionCookie, &session); err != nil || time.Now().After(session.Expires) {
+		return Session{}, false
+	}
+
+	return session, true
+}
+
+func (a App) newSession(claims idClaims, now time.Time) Session {
+	session := Session{
+		Expires: now.Add(a.duration),
+		Subject: claims.Subject,
+		Name:    claims.displayName(),
+	}
+
+	var visibility []string
+
+	for _, group := range claims.Groups {
+		for _, value := range a.groups[group] {
+			if value == allRepositories {
+				session.All = true
+				return session
+			}
+
+			visibility = append(visibility, value)
+		}
+	}
+
+	// without mapped group, only public repositories are visible, like anonymous readers
+	if len(visibility) == 0 {
+		visibility = model.Token{}.Visibility()
+	}
+
+	session.Visibility = visibility
+
+	return session
+}
+
+// setCookie writes the value signed with the name of the cookie, so a cookie can't be replayed as another one
+func (a App) setCookie(w http.ResponseWriter, name, path string, value any, expires time.Time) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal cookie: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    encoded + "." + a.sign(name, encoded),
+		Path:     path,
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   a.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
+
+func (a App) readCookie(r *http.Request, name string, value any) error {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return err
+	}
+
+	encoded, signature, ok := strings.Cut(cookie.Value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(a.sign(name, encoded))) {
+		return errInvalidCookie
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return errInvalidCookie
+	}
+
+	return json.Unmarshal(payload, value)
+}
+
+func (a App) clearCookie(w http.ResponseWriter, name, path string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Path:     path,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   a.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (a App) sign(name, content string) string {
+	mac := hmac.New(sha256.New, a.cookieKey)
+	mac.Write([]byte(name + "=" + content))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
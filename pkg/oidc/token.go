@@ -0,0 +1,322 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ViBiOh/httputils/v4/pkg/httpjson"
+	"github.com/ViBiOh/httputils/v4/pkg/request"
+)
+
+const (
+	// clockSkew tolerates a difference of time with the provider
+	clockSkew = time.Minute
+
+	// keysRefreshDelay limits fetching the keys of the provider when an unknown key is used
+	keysRefreshDelay = time.Minute
+)
+
+// provider caches the discovery document and the keys of the issuer, they are fetched on first use
+type provider struct {
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+	discovery   *discovery
+	issuer      string
+	mutex       sync.Mutex
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+func (p *provider) discover(ctx context.Context) (discovery, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.discovery != nil {
+		return *p.discovery, nil
+	}
+
+	resp, err := request.Get(p.issuer+"/.well-known/openid-configuration").Send(ctx, nil)
+	if err != nil {
+		return discovery{}, fmt.Errorf("fetch discovery: %w", err)
+	}
+
+	var output discovery
+	if err = httpjson.Read(resp, &output); err != nil {
+		return discovery{}, fmt.Errorf("read discovery: %w", err)
+	}
+
+	if output.Issuer != p.issuer {
+		return discovery{}, fmt.Errorf("discovery is for issuer `%s`", output.Issuer)
+	}
+
+	p.discovery = &output
+
+	return output, nil
+}
+
+func (p *provider) authorizationEndpoint(ctx context.Context) (string, error) {
+	config, err := p.discover(ctx)
+	return config.AuthorizationEndpoint, err
+}
+
+// key returns the key of the given id, keys are fetched again if it's unknown
+func (p *provider) key(ctx context.Context, id string) (*rsa.PublicKey, error) {
+	config, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if key, ok := p.keys[id]; ok {
+		return key, nil
+	}
+
+	if time.Since(p.keysFetched) < keysRefreshDelay {
+		return nil, fmt.Errorf("unknown key `%s`", id)
+	}
+
+	if p.keys, err = fetchKeys(ctx, config.JwksURI); err != nil {
+		return nil, err
+	}
+
+	p.keysFetched = time.Now()
+
+	if key, ok := p.keys[id]; ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown key `%s`", id)
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// fetchKeys reads the RSA keys of the provider, other keys are ignored
+func fetchKeys(ctx context.Context, uri string) (map[string]*rsa.PublicKey, error) {
+	resp, err := request.Get(uri).Send(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch keys: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err = httpjson.Read(resp, &set); err != nil {
+		return nil, fmt.Errorf("read keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+
+	for _, item := range set.Keys {
+		if item.Kty != "RSA" {
+			continue
+		}
+
+		modulus, err := base64.RawURLEncoding.DecodeString(item.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode modulus of key `%s`: %w", item.Kid, err)
+		}
+
+		exponent, err := base64.RawURLEncoding.DecodeString(item.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode exponent of key `%s`: %w", item.Kid, err)
+		}
+
+		keys[item.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(new(big.Int).SetBytes(exponent).Int64())}
+	}
+
+	return keys, nil
+}
+
+// exchange redeems the authorization code and returns the claims of the verified ID token
+func (a App) exchange(ctx context.Context, code, verifier, nonce string) (idClaims, error) {
+	config, err := a.provider.discover(ctx)
+	if err != nil {
+		return idClaims{}, err
+	}
+
+	resp, err := request.Post(config.TokenEndpoint).AcceptJSON().BasicAuth(url.QueryEscape(a.clientID), url.QueryEscape(a.secret)).Form(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {a.redirectURL},
+		"code_verifier": {verifier},
+	})
+	if err != nil {
+		return idClaims{}, fmt.Errorf("redeem code: %w", err)
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+
+	if err = httpjson.Read(resp, &tokens); err != nil {
+		return idClaims{}, fmt.Errorf("read tokens: %w", err)
+	}
+
+	claims, err := a.verify(ctx, tokens.IDToken, time.Now())
+	if err != nil {
+		return idClaims{}, err
+	}
+
+	if claims.Nonce != nonce {
+		return idClaims{}, errors.New("invalid nonce")
+	}
+
+	return claims, nil
+}
+
+type idClaims struct {
+	Issuer            string     `json:"iss"`
+	Subject           string     `json:"sub"`
+	Nonce             string     `json:"nonce"`
+	Name              string     `json:"name"`
+	PreferredUsername string     `json:"preferred_username"`
+	Email             string     `json:"email"`
+	Audience          stringList `json:"aud"`
+	Groups            []string   `json:"-"`
+	Expiration        int64      `json:"exp"`
+}
+
+func (c idClaims) displayName() string {
+	for _, name := range []string{c.Name, c.PreferredUsername, c.Email} {
+		if len(name) != 0 {
+			return name
+		}
+	}
+
+	return c.Subject
+}
+
+// stringList is a claim given as a string or an array of strings
+type stringList []string
+
+func (s *stringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = stringList{single}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]string)(s))
+}
+
+func (s stringList) contains(value string) bool {
+	for _, item := range s {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verify checks the RS256 signature of the ID token and its issuer, audience and expiration
+func (a App) verify(ctx context.Context, raw string, now time.Time) (idClaims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return idClaims{}, errors.New("malformed ID token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return idClaims{}, fmt.Errorf("decode header: %w", err)
+	}
+
+	if header.Alg != "RS256" {
+		return idClaims{}, fmt.Errorf("unsupported algorithm `%s`", header.Alg)
+	}
+
+	key, err := a.provider.key(ctx, header.Kid)
+	if err != nil {
+		return idClaims{}, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return idClaims{}, fmt.Errorf("decode signature: %w", err)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return idClaims{}, fmt.Errorf("verify signature: %w", err)
+	}
+
+	var claims idClaims
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return idClaims{}, fmt.Errorf("decode claims: %w", err)
+	}
+
+	if claims.Issuer != a.provider.issuer {
+		return idClaims{}, fmt.Errorf("invalid issuer `%s`", claims.Issuer)
+	}
+
+	if !claims.Audience.contains(a.clientID) {
+		return idClaims{}, errors.New("invalid audience")
+	}
+
+	if now.Add(-clockSkew).After(time.Unix(claims.Expiration, 0)) {
+		return idClaims{}, errors.New("expired ID token")
+	}
+
+	if claims.Groups, err = a.groupsOf(parts[1]); err != nil {
+		return idClaims{}, err
+	}
+
+	return claims, nil
+}
+
+// groupsOf reads the groups claim, a string or an array of strings
+func (a App) groupsOf(segment string) ([]string, error) {
+	var raw map[string]json.RawMessage
+	if err := decodeSegment(segment, &raw); err != nil {
+		return nil, fmt.Errorf("decode claims: %w", err)
+	}
+
+	value, ok := raw[a.groupsClaim]
+	if !ok {
+		return nil, nil
+	}
+
+	var groups stringList
+	if err := json.Unmarshal(value, &groups); err != nil {
+		return nil, fmt.Errorf("decode `%s` claim: %w", a.groupsClaim, err)
+	}
+
+	return groups, nil
+}
+
+func decodeSegment(segment string, value any) error {
+	payload, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, value)
+}
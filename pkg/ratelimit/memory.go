@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	updated time.Time
+	full    time.Time
+	tokens  float64
+}
+
+// memoryBuckets keeps buckets of a single instance, used when no Redis is configured
+type memoryBuckets struct {
+	swept   time.Time
+	clock   func() time.Time
+	buckets map[string]*bucket
+	mutex   sync.Mutex
+}
+
+func newMemoryBuckets() *memoryBuckets {
+	return &memoryBuckets{
+		swept:   time.Now(),
+		clock:   time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (m *memoryBuckets) take(_ context.Context, key string, limit Limit) (time.Duration, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := m.clock()
+	m.sweep(now)
+
+	burst := float64(limit.Burst)
+	perMinute := float64(limit.Rate)
+
+	current, ok := m.buckets[key]
+	if !ok {
+		current = &bucket{updated: now, tokens: burst}
+		m.buckets[key] = current
+	}
+
+	current.tokens = math.Min(burst, current.tokens+now.Sub(current.updated).Minutes()*perMinute)
+	current.updated = now
+
+	var wait time.Duration
+	if current.tokens >= 1 {
+		current.tokens--
+	} else {
+		wait = time.Duration((1 - current.tokens) / perMinute * float64(time.Minute))
+	}
+
+	current.full = now.Add(time.Duration((burst - current.tokens) / perMinute * float64(time.Minute)))
+
+	return wait, nil
+}
+
+// sweep removes full buckets, they are recreated as is on the next request
+func (m *memoryBuckets) sweep(now time.Time) {
+	if now.Sub(m.swept) < sweepInterval {
+		return
+	}
+
+	m.swept = now
+
+	for key, current := range m.buckets {
+		if !now.Before(current.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+)
+
+// Limit is a token bucket refilled with Rate requests per minute, holding up to Burst requests
+type Limit struct {
+	Rate  uint
+	Burst uint
+}
+
+type buckets interface {
+	// take consumes a request of the bucket and returns zero, or the wait before the next request if the bucket is empty
+	take(ctx context.Context, key string, limit Limit) (time.Duration, error)
+}
+
+type App struct {
+	buckets buckets
+	token   Limit
+	ip      Limit
+}
+
+type Config struct {
+	tokenRate  *uint
+	tokenBurst *uint
+	ipRate     *uint
+	ipBurst    *uint
+}
+
+func Flags(fs *flag.FlagSet, prefix string) Config {
+	return Config{
+		tokenRate:  flags.New("TokenRate", "Requests per minute of a token, 0 to disable").Prefix(prefix).DocPrefix("ratelimit").Uint(fs, 600, nil),
+		tokenBurst: flags.New("TokenBurst", "Requests of a token at once").Prefix(prefix).DocPrefix("ratelimit").Uint(fs, 100, nil),
+		ipRate:     flags.New("IpRate", "Requests per minute of an anonymous client IP, 0 to disable").Prefix(prefix).DocPrefix("ratelimit").Uint(fs, 120, nil),
+		ipBurst:    flags.New("IpBurst", "Requests of an anonymous client IP at once").Prefix(prefix).DocPrefix("ratelimit").Uint(fs, 30, nil),
+	}
+}
+
+// New creates the app, buckets are shared by replicas in Redis if enabled, kept in memory otherwise
+func New(config Config, redisClient redis.Client) App {
+	app := App{
+		token: newLimit(*config.tokenRate, *config.tokenBurst),
+		ip:    newLimit(*config.ipRate, *config.ipBurst),
+	}
+
+	if redisClient.Enabled() {
+		app.buckets = newRedisBuckets(redisClient)
+	} else {
+		app.buckets = newMemoryBuckets()
+	}
+
+	return app
+}
+
+func newLimit(rate, burst uint) Limit {
+	if burst == 0 {
+		burst = 1
+	}
+
+	return Limit{Rate: rate, Burst: burst}
+}
+
+// Token consumes a request of the token, it returns false with the wait before the next one if the limit is reached
+func (a App) Token(ctx context.Context, name string) (time.Duration, bool) {
+	return a.allow(ctx, "token:"+name, a.token)
+}
+
+// IP consumes a request of the anonymous client, it returns false with the wait before the next one if the limit is reached
+func (a App) IP(ctx context.Context, ip string) (time.Duration, bool) {
+	return a.allow(ctx, "ip:"+ip, a.ip)
+}
+
+func (a App) allow(ctx context.Context, key string, limit Limit) (time.Duration, bool) {
+	if a.buckets == nil || limit.Rate == 0 {
+		return 0, true
+	}
+
+	wait, err := a.buckets.take(ctx, key, limit)
+	if err != nil {
+		// an unavailable backend doesn't block requests
+		logger.WithField("key", key).Error("rate limit: %s", err)
+		return 0, true
+	}
+
+	return wait, wait == 0
+}
+
+// TooManyRequests writes the 429 response, with the seconds to wait in the Retry-After header
+func TooManyRequests(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Add("Cache-Control", "no-cache")
+	w.Header().Set("Retry-After", strconv.FormatInt(RetryAfter(wait), 10))
+	http.Error(w, "too many requests, retry later", http.StatusTooManyRequests)
+}
+
+// RetryAfter rounds the wait up to the second
+func RetryAfter(wait time.Duration) int64 {
+	seconds := int64((wait + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+
+	return seconds
+}
@@ -0,0 +1,161 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMemoryBuckets(t *testing.T) {
+	now := time.Date(2023, 7, 20, 9, 0, 0, 0, time.UTC)
+	limit := Limit{Rate: 60, Burst: 2}
+
+	cases := map[string]struct {
+		run  func(*memoryBuckets) time.Duration
+		want time.Duration
+	}{
+		"burst": {
+			func(instance *memoryBuckets) time.Duration {
+				instance.take(context.Background(), "ci", limit)
+				wait, _ := instance.take(context.Background(), "ci", limit)
+				return wait
+			},
+			0,
+		},
+		"empty": {
+			func(instance *memoryBuckets) time.Duration {
+				instance.take(context.Background(), "ci", limit)
+				instance.take(context.Background(), "ci", limit)
+				wait, _ := instance.take(context.Background(), "ci", limit)
+				return wait
+			},
+			time.Second,
+		},
+		"other key": {
+			func(instance *memoryBuckets) time.Duration {
+				instance.take(context.Background(), "ci", limit)
+				instance.take(context.Background(), "ci", limit)
+				wait, _ := instance.take(context.Background(), "release", limit)
+				return wait
+			},
+			0,
+		},
+		"refill": {
+			func(instance *memoryBuckets) time.Duration {
+				instance.take(context.Background(), "ci", limit)
+				instance.take(context.Background(), "ci", limit)
+				instance.clock = func() time.Time { return now.Add(time.Second) }
+				wait, _ := instance.take(context.Background(), "ci", limit)
+				return wait
+			},
+			0,
+		},
+		"partial refill": {
+			func(instance *memoryBuckets) time.Duration {
+				instance.take(context.Background(), "ci", limit)
+				instance.take(context.Background(), "ci", limit)
+				instance.clock = func() time.Time { return now.Add(time.Millisecond * 400) }
+				wait, _ := instance.take(context.Background(), "ci", limit)
+				return wait
+			},
+			time.Millisecond * 600,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			instance := newMemoryBuckets()
+			instance.clock = func() time.Time { return now }
+
+			if got := tc.run(instance); got.Round(time.Millisecond) != tc.want {
+				t.Errorf("take() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSweep(t *testing.T) {
+	now := time.Date(2023, 7, 20, 9, 0, 0, 0, time.UTC)
+
+	instance := newMemoryBuckets()
+	instance.swept = now
+	instance.clock = func() time.Time { return now }
+
+	instance.take(context.Background(), "ci", Limit{Rate: 60, Burst: 10})
+	for i := 0; i < 3; i++ {
+		instance.take(context.Background(), "release", Limit{Rate: 1, Burst: 10})
+	}
+
+	instance.sweep(now.Add(sweepInterval))
+
+	if _, ok := instance.buckets["ci"]; ok {
+		t.Error("sweep() kept the full bucket")
+	}
+
+	if _, ok := instance.buckets["release"]; !ok {
+		t.Error("sweep() removed the bucket being refilled")
+	}
+}
+
+func TestAllow(t *testing.T) {
+	cases := map[string]struct {
+		instance App
+		want     bool
+	}{
+		"disabled": {
+			App{},
+			true,
+		},
+		"no rate": {
+			App{buckets: newMemoryBuckets(), ip: Limit{Rate: 0, Burst: 1}},
+			true,
+		},
+		"limited": {
+			App{buckets: newMemoryBuckets(), ip: Limit{Rate: 1, Burst: 1}},
+			false,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			tc.instance.IP(context.Background(), "192.0.2.1")
+
+			if _, got := tc.instance.IP(context.Background(), "192.0.2.1"); got != tc.want {
+				t.Errorf("IP() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTooManyRequests(t *testing.T) {
+	cases := map[string]struct {
+		wait time.Duration
+		want string
+	}{
+		"rounded up": {
+			time.Millisecond * 1200,
+			"2",
+		},
+		"at least a second": {
+			time.Millisecond,
+			"1",
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			writer := httptest.NewRecorder()
+			TooManyRequests(writer, tc.wait)
+
+			if writer.Code != http.StatusTooManyRequests {
+				t.Errorf("TooManyRequests() = %d, want %d", writer.Code, http.StatusTooManyRequests)
+			}
+
+			if got := writer.Header().Get("Retry-After"); got != tc.want {
+				t.Errorf("TooManyRequests() Retry-After = `%s`, want `%s`", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/version"
+	"github.com/ViBiOh/httputils/v4/pkg/redis"
+)
+
+// takeScript refills and consumes the bucket atomically with the clock of Redis, so replicas share it. It returns the milliseconds to wait, 0 if allowed.
+const takeScript = `
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(bucket[1]) or burst
+local updated = tonumber(bucket[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate)
+
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+else
+  wait = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate) + 1000)
+
+return wait
+`
+
+type redisBuckets struct {
+	client redis.Client
+}
+
+func newRedisBuckets(client redis.Client) redisBuckets {
+	return redisBuckets{
+		client: client,
+	}
+}
+
+func (r redisBuckets) take(ctx context.Context, key string, limit Limit) (time.Duration, error) {
+	pipeline := r.client.Pipeline()
+
+	perMillisecond := float64(limit.Rate) / float64(time.Minute/time.Millisecond)
+	result := pipeline.Eval(ctx, takeScript, []string{version.Redis("ratelimit:" + key)}, limit.Burst, perMillisecond)
+
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("take: %w", err)
+	}
+
+	wait, err := result.Int64()
+	if err != nil {
+		return 0, fmt.Errorf("read wait: %w", err)
+	}
+
+	return time.Duration(wait) * time.Millisecond, nil
+}
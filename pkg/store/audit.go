@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const insertAuditQuery = `
+INSERT INTO
+  herodote.audit
+(
+  date,
+  actor,
+  tenant,
+  ip,
+  method,
+  path,
+  repository,
+  hash,
+  status
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9
+)
+`
+
+func (a App) SaveAudit(ctx context.Context, entry model.AuditEntry) error {
+	return a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.Exec(ctx, insertAuditQuery, entry.Date, entry.Actor, entry.Tenant, entry.IP, entry.Method, entry.Path, entry.Repository, entry.Hash, entry.Status)
+	})
+}
+
+const listAuditQuery = `
+SELECT
+  id,
+  date,
+  actor,
+  tenant,
+  ip,
+  method,
+  path,
+  repository,
+  hash,
+  status,
+  count(1) OVER() AS full_count
+FROM
+  herodote.audit
+WHERE
+  ($1 = '' OR actor = $1)
+  AND ($2 = '' OR repository = $2)
+  AND ($3 = '' OR method = $3)
+  AND ($4::TIMESTAMPTZ IS NULL OR date >= $4)
+  AND ($5::TIMESTAMPTZ IS NULL OR date < $5)
+  AND ($6 = 0 OR id < $6)
+ORDER BY
+  id DESC
+LIMIT $7
+`
+
+// ListAudit returns the most recent entries first, the page starts before the id of the last entry of the previous one, if any
+func (a App) ListAudit(ctx context.Context, filter model.AuditFilter, pageSize uint, last uint64) ([]model.AuditEntry, uint, error) {
+	var totalCount uint
+	var list []model.AuditEntry
+
+	scanner := func(rows pgx.Rows) error {
+		var item model.AuditEntry
+		if err := rows.Scan(&item.ID, &item.Date, &item.Actor, &item.Tenant, &item.IP, &item.Method, &item.Path, &item.Repository, &item.Hash, &item.Status, &totalCount); err != nil {
+			return err
+		}
+
+		list = append(list, item)
+		return nil
+	}
+
+	err := a.db.List(ctx, scanner, listAuditQuery, filter.Actor, filter.Repository, filter.Method, dateValue(filter.After), dateValue(filter.Before), last, pageSize)
+
+	return list, totalCount, err
+}
+
+const purgeAuditQuery = `
+WITH deleted AS (
+  DELETE FROM
+    herodote.audit
+  WHERE
+    date < $1
+  RETURNING
+    id
+) SELECT
+  count(1)
+FROM
+  deleted
+`
+
+// PurgeAudit deletes entries older than the given date and returns their count
+func (a App) PurgeAudit(ctx context.Context, before time.Time) (uint64, error) {
+	var count uint64
+
+	err := a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.Get(ctx, func(row pgx.Row) error {
+			return row.Scan(&count)
+		}, purgeAuditQuery, before)
+	})
+
+	return count, err
+}
+
+// dateValue sends a zero date as NULL
+func dateValue(date time.Time) any {
+	if date.IsZero() {
+		return nil
+	}
+
+	return date
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// deploymentCommitJoin finds the saved commit of the deployment aliased `d`, hashes may be abbreviated on both sides
+const deploymentCommitJoin = `
+LEFT JOIN LATERAL (
+  SELECT
+    c.hash,
+    c.date
+  FROM
+    herodote.commit AS c
+  WHERE
+    c.repository = d.repository
+    AND (starts_with(c.hash, d.hash) OR starts_with(d.hash, c.hash))
+  ORDER BY
+    c.date DESC
+  LIMIT 1
+) AS c ON TRUE
+`
+
+const insertDeploymentQuery = `
+INSERT INTO
+  herodote.deployment
+(
+  repository,
+  environment,
+  hash,
+  status,
+  date
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5
+) RETURNING
+  id
+`
+
+func (a App) CreateDeployment(ctx context.Context, deployment model.Deployment) (uint64, error) {
+	var id uint64
+
+	err := a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.Get(ctx, func(row pgx.Row) error {
+			return row.Scan(&id)
+		}, insertDeploymentQuery, deployment.Repository, deployment.Environment, deployment.Hash, deployment.Status, deployment.Date)
+	})
+
+	return id, err
+}
+
+const listDeploymentsQuery = `
+SELECT
+  d.id,
+  d.repository,
+  d.environment,
+  d.hash,
+  d.status,
+  d.date,
+  COALESCE(c.hash, ''),
+  COALESCE(c.date, d.date)
+FROM
+  herodote.deployment AS d
+` + deploymentCommitJoin + `
+WHERE
+  ($1 = '' OR d.repository = $1)
+  AND ($2 = '' OR d.environment = $2)
+  AND ($3 = '' OR d.status = $3)
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      herodote.private_repository AS p
+    WHERE
+      p.repository = d.repository
+      AND p.tenant <> ALL($5)
+      AND NOT p.repository LIKE ANY($6)
+  )
+ORDER BY
+  d.date DESC,
+  d.id DESC
+LIMIT $4
+`
+
+// ListDeployments returns the most recent deployments first, of repositories visible with the given visibility, empty parameters match every deployment
+func (a App) ListDeployments(ctx context.Context, repository, environment, status string, visibility []string, pageSize uint) ([]model.Deployment, error) {
+	tenants, patterns := visibilityArgs(visibility)
+
+	return a.listDeployments(ctx, listDeploymentsQuery, repository, environment, status, pageSize, tenants, patterns)
+}
+
+const listCurrentDeploymentsQuery = `
+SELECT
+  d.id,
+  d.repository,
+  d.environment,
+  d.hash,
+  d.status,
+  d.date,
+  COALESCE(c.hash, ''),
+  COALESCE(c.date, d.date)
+FROM (
+  SELECT DISTINCT ON (repository, environment)
+    id,
+    repository,
+    environment,
+    hash,
+    status,
+    date
+  FROM
+    herodote.deployment
+  WHERE
+    status = 'success'
+    AND (cardinality($1::TEXT[]) = 0 OR repository = ANY($1))
+  ORDER BY
+    repository,
+    environment,
+    date DESC,
+    id DESC
+) AS d
+` + deploymentCommitJoin + `
+ORDER BY
+  d.repository,
+  d.environment
+`
+
+// ListCurrentDeployments returns the last successful deployment of each environment of the repositories, or of every repository if empty
+func (a App) ListCurrentDeployments(ctx context.Context, repositories []string) ([]model.Deployment, error) {
+	return a.listDeployments(ctx, listCurrentDeploymentsQuery, arrayValue(repositories))
+}
+
+func (a App) listDeployments(ctx context.Context, query string, args ...any) ([]model.Deployment, error) {
+	var list []model.Deployment
+
+	scanner := func(rows pgx.Rows) error {
+		var item model.Deployment
+		if err := rows.Scan(&item.ID, &item.Repository, &item.Environment, &item.Hash, &item.Status, &item.Date, &item.Commit, &item.CommitDate); err != nil {
+			return err
+		}
+
+		list = append(list, item)
+		return nil
+	}
+
+	err := a.db.List(ctx, scanner, query, args...)
+
+	return list, err
+}
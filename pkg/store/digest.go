@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const digestCountsQuery = `
+SELECT
+  repository,
+  type,
+  count(1)
+FROM
+  herodote.commit AS c
+WHERE
+  date >= $1
+  AND date < $2
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      herodote.private_repository AS p
+    WHERE
+      p.repository = c.repository
+  )
+GROUP BY
+  repository,
+  type
+ORDER BY
+  repository ASC,
+  type ASC
+`
+
+const digestHighlightsQuery = `
+SELECT
+  hash,
+  type,
+  component,
+  revert,
+  breaking,
+  content,
+  date,
+  remote,
+  repository
+FROM
+  herodote.commit AS c
+WHERE
+  date >= $1
+  AND date < $2
+  AND (breaking OR revert)
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      herodote.private_repository AS p
+    WHERE
+      p.repository = c.repository
+  )
+ORDER BY
+  date DESC
+LIMIT $3
+`
+
+// Digest counts commits of public repositories in the period by repository and lists the most recent breaking changes and reverts
+func (a App) Digest(ctx context.Context, since, until time.Time, highlights uint) (model.Digest, error) {
+	digest := model.Digest{
+		Since: since,
+		Until: until,
+	}
+
+	countsScanner := func(rows pgx.Rows) error {
+		var repository, commitType string
+		var count uint64
+
+		if err := rows.Scan(&repository, &commitType, &count); err != nil {
+			return err
+		}
+
+		if last := len(digest.Repositories) - 1; last < 0 || digest.Repositories[last].Repository != repository {
+			digest.Repositories = append(digest.Repositories, model.RepositoryDigest{
+				Repository: repository,
+				Types:      make(map[string]uint64),
+			})
+		}
+
+		current := &digest.Repositories[len(digest.Repositories)-1]
+		current.Types[commitType] = count
+		current.Count += count
+
+		return nil
+	}
+
+	if err := a.db.List(ctx, countsScanner, digestCountsQuery, since, until); err != nil {
+		return digest, err
+	}
+
+	highlightsScanner := func(rows pgx.Rows) error {
+		var item model.Commit
+
+		if err := rows.Scan(&item.Hash, &item.Type, &item.Component, &item.Revert, &item.Breaking, &item.Content, &item.Date, &item.Remote, &item.Repository); err != nil {
+			return err
+		}
+
+		digest.Highlights = append(digest.Highlights, item)
+		return nil
+	}
+
+	err := a.db.List(ctx, highlightsScanner, digestHighlightsQuery, since, until, highlights)
+
+	return digest, err
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// exportBatchSize is the number of commits read by query, the memory used by an export doesn't depend on its size
+const exportBatchSize = 1000
+
+const exportCommitQuery = `
+SELECT
+  id,
+  hash,
+  type,
+  component,
+  revert,
+  breaking,
+  content,
+  date,
+  remote,
+  repository,
+  paths,
+  refs,
+  picked_from,
+` + commitBranchesColumn + `
+FROM
+  herodote.commit AS c
+WHERE
+  id > $1
+`
+
+const exportCommitTail = `
+ORDER BY
+  id ASC
+LIMIT $2
+`
+
+// ExportCommits calls the handler with every commit matching the search, in the order of saving, by batches
+func (a App) ExportCommits(ctx context.Context, query string, filters map[string][]string, before, after string, handler func(model.CommitEvent) error) error {
+	var words []string
+	if len(query) > 0 {
+		words = strings.Split(query, " ")
+	}
+
+	var lastID uint64
+
+	for {
+		batch := make([]model.CommitEvent, 0, exportBatchSize)
+
+		scanner := func(rows pgx.Rows) error {
+			var item model.CommitEvent
+			var references []string
+
+			if err := rows.Scan(&item.ID, &item.Commit.Hash, &item.Commit.Type, &item.Commit.Component, &item.Commit.Revert, &item.Commit.Breaking, &item.Commit.Content, &item.Commit.Date, &item.Commit.Remote, &item.Commit.Repository, &item.Commit.Paths, &references, &item.Commit.PickedFrom, &item.Commit.Branches); err != nil {
+				return err
+			}
+
+			item.Commit.References = model.NewReferences(references)
+
+			batch = append(batch, item)
+			return nil
+		}
+
+		sqlQuery, sqlArgs := computeExportQuery(lastID, words, filters, before, after)
+
+		// the batch is handled once read, a slow handler doesn't hold the query
+		if err := a.db.List(ctx, scanner, sqlQuery, sqlArgs...); err != nil {
+			return err
+		}
+
+		for _, item := range batch {
+			if err := handler(item); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+func computeExportQuery(lastID uint64, words []string, filters map[string][]string, before, after string) (string, []any) {
+	query := strings.Builder{}
+	query.WriteString(exportCommitQuery)
+
+	args := []any{
+		lastID,
+		exportBatchSize,
+	}
+
+	args = computeFiltersQuery(&query, args, words, filters)
+	args = computeDateQuery(&query, args, before, "", after)
+
+	query.WriteString(exportCommitTail)
+
+	return query.String(), args
+}
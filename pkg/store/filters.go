@@ -6,15 +6,32 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// listFiltersQuery lists values of repositories visible with the given tenants or patterns, a NULL array of tenants makes every repository visible
 const listFiltersQuery = `
-SELECT
+SELECT DISTINCT
   kind,
   value
 FROM
-  herodote.filters
+  herodote.filters AS f
+WHERE
+  NOT EXISTS (
+    SELECT
+      1
+    FROM
+      herodote.private_repository AS p
+    WHERE
+      p.repository = f.repository
+      AND p.tenant <> ALL($1)
+      AND NOT p.repository LIKE ANY($2)
+  )
+ORDER BY
+  value ASC
 `
 
-func (a App) ListFilters(ctx context.Context) (map[string][]string, error) {
+// ListFilters returns values of commits' fields, of repositories visible with the given visibility, see model.Token.Visibility
+func (a App) ListFilters(ctx context.Context, visibility []string) (map[string][]string, error) {
+	tenants, patterns := visibilityArgs(visibility)
+
 	list := make(map[string][]string)
 
 	scanner := func(rows pgx.Rows) error {
@@ -32,5 +49,7 @@ func (a App) ListFilters(ctx context.Context) (map[string][]string, error) {
 		return nil
 	}
 
-	return list, a.db.List(ctx, scanner, listFiltersQuery)
+	err := a.db.List(ctx, scanner, listFiltersQuery, tenants, patterns)
+
+	return list, err
 }
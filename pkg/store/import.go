@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const upsertCommitQuery = `
+INSERT INTO
+  herodote.commit AS c
+(
+  hash,
+  type,
+  component,
+  revert,
+  breaking,
+  content,
+  date,
+  remote,
+  repository,
+  paths,
+  refs,
+  picked_from,
+  tenant,
+  search_vector
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  to_timestamp($7),
+  $8,
+  $9,
+  $10,
+  $11,
+  $12,
+  $13,
+  to_tsvector('english', $1) || to_tsvector('english', $2) || to_tsvector('english', $3) || to_tsvector('english', $6)
+) ON CONFLICT (repository, hash) DO UPDATE SET
+  type = EXCLUDED.type,
+  component = EXCLUDED.component,
+  revert = EXCLUDED.revert,
+  breaking = EXCLUDED.breaking,
+  content = EXCLUDED.content,
+  date = EXCLUDED.date,
+  remote = EXCLUDED.remote,
+  paths = EXCLUDED.paths,
+  refs = EXCLUDED.refs,
+  picked_from = EXCLUDED.picked_from,
+  tenant = COALESCE(NULLIF(EXCLUDED.tenant, ''), c.tenant),
+  search_vector = EXCLUDED.search_vector
+RETURNING
+  xmax = 0
+`
+
+// ImportCommits inserts or updates commits by repository and hash in a single transaction, branches are added and an empty tenant keeps the existing one.
+// It returns the count of created and updated commits.
+func (a App) ImportCommits(ctx context.Context, commits []model.Commit) (uint64, uint64, error) {
+	var created, updated uint64
+
+	err := a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		for _, o := range commits {
+			var inserted bool
+
+			if err := a.db.Get(ctx, func(row pgx.Row) error {
+				return row.Scan(&inserted)
+			}, upsertCommitQuery, o.Hash, o.Type, o.Component, o.Revert, o.Breaking, o.Content, o.Date.Unix(), o.Remote, o.Repository, arrayValue(o.Paths), arrayValue(model.ReferenceKeys(o.References)), o.PickedFrom, o.Tenant); err != nil {
+				return err
+			}
+
+			if err := a.saveBranches(ctx, o); err != nil {
+				return err
+			}
+
+			if inserted {
+				created++
+			} else {
+				updated++
+			}
+		}
+
+		return nil
+	})
+
+	return created, updated, err
+}
+
+const listCommitBranchesQuery = `
+SELECT
+  c.hash,
+  b.branch
+FROM
+  herodote.commit AS c
+  LEFT JOIN herodote.commit_branch AS b ON b.repository = c.repository AND b.hash = c.hash
+WHERE
+  c.repository = $1
+`
+
+// ListCommitBranches returns hashes of every commit of a repository, with their branches
+func (a App) ListCommitBranches(ctx context.Context, repository string) (map[string][]string, error) {
+	output := make(map[string][]string)
+
+	scanner := func(rows pgx.Rows) error {
+		var hash string
+		var branch *string
+
+		if err := rows.Scan(&hash, &branch); err != nil {
+			return err
+		}
+
+		if branch != nil {
+			output[hash] = append(output[hash], *branch)
+		} else {
+			output[hash] = nil
+		}
+
+		return nil
+	}
+
+	err := a.db.List(ctx, scanner, listCommitBranchesQuery, repository)
+
+	return output, err
+}
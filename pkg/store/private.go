@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// visibilityCondition hides rows of private repositories of other tenants and not matching the patterns, `%s` is the column of the repository,
+// the `%d` are the indexes of the tenants and the patterns, see visibilityArgs. A NULL array of tenants makes every repository visible.
+const visibilityCondition = " AND NOT EXISTS (SELECT 1 FROM herodote.private_repository AS p WHERE p.repository = %s AND p.tenant <> ALL($%d) AND NOT p.repository LIKE ANY($%d))"
+
+// visibilityArgs returns the tenants and the LIKE patterns of a visibility, see model.SplitVisibility
+func visibilityArgs(visibility []string) ([]string, []string) {
+	tenants, patterns := model.SplitVisibility(visibility)
+
+	for i, pattern := range patterns {
+		patterns[i] = likePattern(pattern)
+	}
+
+	return tenants, patterns
+}
+
+var wildcardReplacer = strings.NewReplacer("*", "%", "?", "_")
+
+// likePattern converts a repository pattern (e.g. `acme/*`) to a LIKE one
+func likePattern(pattern string) string {
+	return wildcardReplacer.Replace(likeEscaper.Replace(pattern))
+}
+
+const listPrivateRepositoriesQuery = `
+SELECT
+  repository,
+  tenant
+FROM
+  herodote.private_repository
+ORDER BY
+  repository ASC
+`
+
+func (a App) ListPrivateRepositories(ctx context.Context) ([]model.PrivateRepository, error) {
+	var list []model.PrivateRepository
+
+	scanner := func(rows pgx.Rows) error {
+		var item model.PrivateRepository
+		if err := rows.Scan(&item.Repository, &item.Tenant); err != nil {
+			return err
+		}
+
+		list = append(list, item)
+		return nil
+	}
+
+	err := a.db.List(ctx, scanner, listPrivateRepositoriesQuery)
+
+	return list, err
+}
+
+const upsertPrivateRepositoryQuery = `
+INSERT INTO
+  herodote.private_repository
+(
+  repository,
+  tenant
+) VALUES (
+  $1,
+  $2
+) ON CONFLICT (repository) DO UPDATE SET
+  tenant = EXCLUDED.tenant
+`
+
+// SetPrivateRepository makes the repository private to the tenant, or changes its tenant
+func (a App) SetPrivateRepository(ctx context.Context, private model.PrivateRepository) error {
+	return a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.Exec(ctx, upsertPrivateRepositoryQuery, private.Repository, private.Tenant)
+	})
+}
+
+const deletePrivateRepositoryQuery = `
+DELETE FROM
+  herodote.private_repository
+WHERE
+  repository = $1
+RETURNING
+  repository
+`
+
+// DeletePrivateRepository makes the repository public
+func (a App) DeletePrivateRepository(ctx context.Context, repository string) error {
+	return a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.Get(ctx, func(row pgx.Row) error {
+			var deleted string
+
+			err := row.Scan(&deleted)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return httpModel.WrapNotFound(fmt.Errorf("private repository `%s` not found", repository))
+			}
+
+			return err
+		}, deletePrivateRepositoryQuery, repository)
+	})
+}
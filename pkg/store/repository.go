@@ -0,0 +1,202 @@
+package store
+
+import (
+	"context"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const listAliasesQuery = `
+SELECT
+  alias,
+  repository
+FROM
+  herodote.repository_alias
+ORDER BY
+  alias ASC
+`
+
+func (a App) ListAliases(ctx context.Context) ([]model.RepositoryAlias, error) {
+	var list []model.RepositoryAlias
+
+	scanner := func(rows pgx.Rows) error {
+		var item model.RepositoryAlias
+		if err := rows.Scan(&item.Alias, &item.Repository); err != nil {
+			return err
+		}
+
+		list = append(list, item)
+		return nil
+	}
+
+	err := a.db.List(ctx, scanner, listAliasesQuery)
+
+	return list, err
+}
+
+const flattenAliasesQuery = `
+UPDATE
+  herodote.repository_alias
+SET
+  repository = $2
+WHERE
+  repository = $1
+`
+
+const upsertAliasQuery = `
+INSERT INTO
+  herodote.repository_alias
+(
+  alias,
+  repository
+) VALUES (
+  $1,
+  $2
+) ON CONFLICT (alias) DO UPDATE SET
+  repository = EXCLUDED.repository
+`
+
+const deleteAliasQuery = `
+DELETE FROM
+  herodote.repository_alias
+WHERE
+  alias = $1
+`
+
+const mergeBranchesQuery = `
+INSERT INTO
+  herodote.commit_branch
+(
+  repository,
+  hash,
+  branch
+) SELECT
+  $2,
+  b.hash,
+  b.branch
+FROM
+  herodote.commit_branch AS b
+WHERE
+  b.repository = $1
+  AND EXISTS (
+    SELECT
+      1
+    FROM
+      herodote.commit
+    WHERE
+      repository = $2
+      AND hash = b.hash
+  )
+ON CONFLICT (repository, hash, branch) DO NOTHING
+`
+
+const deleteMergedCommitsQuery = `
+DELETE FROM
+  herodote.commit AS c
+WHERE
+  c.repository = $1
+  AND EXISTS (
+    SELECT
+      1
+    FROM
+      herodote.commit
+    WHERE
+      repository = $2
+      AND hash = c.hash
+  )
+`
+
+const moveCommitsQuery = `
+WITH moved AS (
+  UPDATE
+    herodote.commit
+  SET
+    repository = $2
+  WHERE
+    repository = $1
+  RETURNING
+    hash
+) SELECT
+  count(1)
+FROM
+  moved
+`
+
+const moveDeploymentsQuery = `
+UPDATE
+  herodote.deployment
+SET
+  repository = $2
+WHERE
+  repository = $1
+`
+
+const movePrivateRepositoryQuery = `
+INSERT INTO
+  herodote.private_repository
+(
+  repository,
+  tenant
+) SELECT
+  $2,
+  tenant
+FROM
+  herodote.private_repository
+WHERE
+  repository = $1
+ON CONFLICT (repository) DO NOTHING
+`
+
+const deleteAliasPrivateRepositoryQuery = `
+DELETE FROM
+  herodote.private_repository
+WHERE
+  repository = $1
+`
+
+// RenameRepository records the alias and moves every commit and deployment of the alias to the repository, commits already present in the repository are kept with the branches of both
+func (a App) RenameRepository(ctx context.Context, alias model.RepositoryAlias) (uint64, error) {
+	var count uint64
+
+	err := a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		if err := a.db.Exec(ctx, flattenAliasesQuery, alias.Alias, alias.Repository); err != nil {
+			return err
+		}
+
+		if err := a.db.Exec(ctx, upsertAliasQuery, alias.Alias, alias.Repository); err != nil {
+			return err
+		}
+
+		if err := a.db.Exec(ctx, deleteAliasQuery, alias.Repository); err != nil {
+			return err
+		}
+
+		if err := a.db.Exec(ctx, mergeBranchesQuery, alias.Alias, alias.Repository); err != nil {
+			return err
+		}
+
+		if err := a.db.Exec(ctx, deleteMergedCommitsQuery, alias.Alias, alias.Repository); err != nil {
+			return err
+		}
+
+		if err := a.db.Exec(ctx, moveDeploymentsQuery, alias.Alias, alias.Repository); err != nil {
+			return err
+		}
+
+		// commits of a private alias stay private
+		if err := a.db.Exec(ctx, movePrivateRepositoryQuery, alias.Alias, alias.Repository); err != nil {
+			return err
+		}
+
+		if err := a.db.Exec(ctx, deleteAliasPrivateRepositoryQuery, alias.Alias); err != nil {
+			return err
+		}
+
+		return a.db.Get(ctx, func(row pgx.Row) error {
+			return row.Scan(&count)
+		}, moveCommitsQuery, alias.Alias, alias.Repository)
+	})
+
+	return count, err
+}
@@ -20,13 +20,20 @@ SELECT
   date,
   remote,
   repository,
+  paths,
+  refs,
+  picked_from,
+  tenant,
+` + commitBranchesColumn + `,
   count(1) OVER() AS full_count
 FROM
-  herodote.commit
+  herodote.commit AS c
 WHERE
   TRUE
 `
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 const searchCommitTail = `
 ORDER BY
   date DESC
@@ -44,11 +51,14 @@ func (a App) SearchCommit(ctx context.Context, query string, filters map[string]
 
 	scanner := func(rows pgx.Rows) error {
 		var item model.Commit
+		var references []string
 
-		if err := rows.Scan(&item.Hash, &item.Type, &item.Component, &item.Revert, &item.Breaking, &item.Content, &item.Date, &item.Remote, &item.Repository, &totalCount); err != nil {
+		if err := rows.Scan(&item.Hash, &item.Type, &item.Component, &item.Revert, &item.Breaking, &item.Content, &item.Date, &item.Remote, &item.Repository, &item.Paths, &references, &item.PickedFrom, &item.Tenant, &item.Branches, &totalCount); err != nil {
 			return err
 		}
 
+		item.References = model.NewReferences(references)
+
 		list = append(list, item)
 		return nil
 	}
@@ -69,6 +79,16 @@ func computeSearchQuery(pageSize uint, last string, words []string, filters map[
 		pageSize,
 	}
 
+	args = computeFiltersQuery(&query, args, words, filters)
+	args = computeDateQuery(&query, args, before, last, after)
+
+	query.WriteString(searchCommitTail)
+
+	return query.String(), args
+}
+
+// computeFiltersQuery appends the full-text search and the filters on commits' fields
+func computeFiltersQuery(query *strings.Builder, args []any, words []string, filters map[string][]string) []any {
 	if len(words) != 0 {
 		args = append(args, strings.Join(words, " & "))
 		query.WriteString(fmt.Sprintf(" AND search_vector @@ to_tsquery('english', $%d)", len(args)))
@@ -79,6 +99,30 @@ func computeSearchQuery(pageSize uint, last string, words []string, filters map[
 			continue
 		}
 
+		if key == "path" {
+			args = computePathQuery(query, args, values)
+			continue
+		}
+
+		if key == "branch" {
+			args = append(args, values)
+			query.WriteString(fmt.Sprintf(" AND EXISTS (SELECT 1 FROM herodote.commit_branch AS b WHERE b.repository = c.repository AND b.hash = c.hash AND b.branch = ANY($%d))", len(args)))
+			continue
+		}
+
+		if key == "visibility" {
+			tenants, patterns := visibilityArgs(values)
+			args = append(args, tenants, patterns)
+			query.WriteString(fmt.Sprintf(visibilityCondition, "c.repository", len(args)-1, len(args)))
+			continue
+		}
+
+		if key == "ref" {
+			args = append(args, values)
+			query.WriteString(fmt.Sprintf(" AND refs && $%d", len(args)))
+			continue
+		}
+
 		sqlValues := make([]string, 0)
 		for _, value := range values {
 			if len(value) == 0 {
@@ -96,11 +140,26 @@ func computeSearchQuery(pageSize uint, last string, words []string, filters map[
 		query.WriteString(fmt.Sprintf(" AND %s = ANY($%d)", key, len(args)))
 	}
 
-	args = computeDateQuery(&query, args, before, last, after)
+	return args
+}
 
-	query.WriteString(searchCommitTail)
+// computePathQuery matches commits that changed a given path or a file below it
+func computePathQuery(query *strings.Builder, args []any, values []string) []any {
+	var prefixes []string
+	for _, value := range values {
+		if len(value) != 0 {
+			prefixes = append(prefixes, likeEscaper.Replace(value)+"/%")
+		}
+	}
 
-	return query.String(), args
+	if len(prefixes) == 0 {
+		return args
+	}
+
+	args = append(args, values, prefixes)
+	query.WriteString(fmt.Sprintf(" AND EXISTS (SELECT 1 FROM unnest(paths) AS changed WHERE changed = ANY($%d) OR changed LIKE ANY($%d))", len(args)-1, len(args)))
+
+	return args
 }
 
 func computeDateQuery(query *strings.Builder, args []any, before, last, after string) []any {
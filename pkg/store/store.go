@@ -2,11 +2,18 @@ package store
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/ViBiOh/herodote/pkg/model"
 	"github.com/ViBiOh/httputils/v4/pkg/db"
+	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+const uniqueViolationCode = "23505"
+
 type App struct {
 	db db.App
 }
@@ -21,6 +28,12 @@ func (a App) Enabled() bool {
 	return a.db.Enabled()
 }
 
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
 const insertCommitQuery = `
 INSERT INTO
   herodote.commit
@@ -34,6 +47,10 @@ INSERT INTO
   date,
   remote,
   repository,
+  paths,
+  refs,
+  picked_from,
+  tenant,
   search_vector
 ) VALUES (
   $1,
@@ -45,14 +62,274 @@ INSERT INTO
   to_timestamp($7),
   $8,
   $9,
+  $10,
+  $11,
+  $12,
+  $13,
   to_tsvector('english', $1) || to_tsvector('english', $2) || to_tsvector('english', $3) || to_tsvector('english', $6)
-)
+) ON CONFLICT (repository, hash) DO NOTHING
+RETURNING
+  id
+`
+
+const insertBranchesQuery = `
+INSERT INTO
+  herodote.commit_branch
+(
+  repository,
+  hash,
+  branch
+) SELECT
+  $1,
+  $2,
+  unnest($3::TEXT[])
+ON CONFLICT (repository, hash, branch) DO NOTHING
+`
+
+// commitBranchesColumn lists branches of the commit aliased `c`
+const commitBranchesColumn = `
+  ARRAY(
+    SELECT
+      b.branch
+    FROM
+      herodote.commit_branch AS b
+    WHERE
+      b.repository = c.repository
+      AND b.hash = c.hash
+    ORDER BY
+      b.branch
+  ) AS branches
+`
+
+// SaveCommit inserts the commit and returns its id, increasing in the order of saving.
+// If the commit already exists, only its branches are added and the returned id is 0.
+func (a App) SaveCommit(ctx context.Context, o model.Commit) (uint64, error) {
+	var id uint64
+
+	err := a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		err := a.db.Get(ctx, func(row pgx.Row) error {
+			return row.Scan(&id)
+		}, insertCommitQuery, o.Hash, o.Type, o.Component, o.Revert, o.Breaking, o.Content, o.Date.Unix(), o.Remote, o.Repository, arrayValue(o.Paths), arrayValue(model.ReferenceKeys(o.References)), o.PickedFrom, o.Tenant)
+
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		return a.saveBranches(ctx, o)
+	})
+
+	return id, err
+}
+
+func (a App) saveBranches(ctx context.Context, o model.Commit) error {
+	if len(o.Branches) == 0 {
+		return nil
+	}
+
+	return a.db.Exec(ctx, insertBranchesQuery, o.Repository, o.Hash, o.Branches)
+}
+
+// arrayValue avoids sending a NULL array, an empty one is stored or matched instead
+func arrayValue(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
+
+const getCommitQuery = `
+SELECT
+  hash,
+  type,
+  component,
+  revert,
+  breaking,
+  content,
+  date,
+  remote,
+  repository,
+  paths,
+  refs,
+  picked_from,
+  tenant,
+` + commitBranchesColumn + `
+FROM
+  herodote.commit AS c
+WHERE
+  repository = $1
+  AND hash = $2
+`
+
+func (a App) GetCommit(ctx context.Context, repository, hash string) (model.Commit, error) {
+	var item model.Commit
+	var references []string
+
+	scanner := func(row pgx.Row) error {
+		err := row.Scan(&item.Hash, &item.Type, &item.Component, &item.Revert, &item.Breaking, &item.Content, &item.Date, &item.Remote, &item.Repository, &item.Paths, &references, &item.PickedFrom, &item.Tenant, &item.Branches)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return httpModel.WrapNotFound(fmt.Errorf("commit `%s` of `%s` not found", hash, repository))
+		}
+
+		item.References = model.NewReferences(references)
+
+		return err
+	}
+
+	err := a.db.Get(ctx, scanner, getCommitQuery, repository, hash)
+
+	return item, err
+}
+
+const findCommitQuery = `
+SELECT
+  hash,
+  type,
+  component,
+  revert,
+  breaking,
+  content,
+  date,
+  remote,
+  repository,
+  paths,
+  refs,
+  picked_from,
+  tenant,
+` + commitBranchesColumn + `
+FROM
+  herodote.commit AS c
+WHERE
+  repository = $1
+  AND (starts_with(hash, $2) OR starts_with($2, hash))
+LIMIT 2
+`
+
+// FindCommit returns the commit of the repository matching the hash, both may be abbreviated
+func (a App) FindCommit(ctx context.Context, repository, hash string) (model.Commit, error) {
+	var list []model.Commit
+
+	scanner := func(rows pgx.Rows) error {
+		var item model.Commit
+		var references []string
+
+		if err := rows.Scan(&item.Hash, &item.Type, &item.Component, &item.Revert, &item.Breaking, &item.Content, &item.Date, &item.Remote, &item.Repository, &item.Paths, &references, &item.PickedFrom, &item.Tenant, &item.Branches); err != nil {
+			return err
+		}
+
+		item.References = model.NewReferences(references)
+
+		list = append(list, item)
+		return nil
+	}
+
+	if err := a.db.List(ctx, scanner, findCommitQuery, repository, hash); err != nil {
+		return model.Commit{}, err
+	}
+
+	switch len(list) {
+	case 0:
+		return model.Commit{}, httpModel.WrapNotFound(fmt.Errorf("commit `%s` of `%s` not found", hash, repository))
+	case 1:
+		return list[0], nil
+	default:
+		return model.Commit{}, httpModel.WrapInvalid(fmt.Errorf("hash `%s` of `%s` is ambiguous", hash, repository))
+	}
+}
+
+const updateCommitQuery = `
+UPDATE
+  herodote.commit
+SET
+  hash = $3,
+  type = $4,
+  component = $5,
+  revert = $6,
+  breaking = $7,
+  content = $8,
+  date = to_timestamp($9),
+  remote = $10,
+  repository = $11,
+  paths = $12,
+  refs = $13,
+  search_vector = to_tsvector('english', $3) || to_tsvector('english', $4) || to_tsvector('english', $5) || to_tsvector('english', $8)
+WHERE
+  repository = $1
+  AND hash = $2
+`
+
+func (a App) UpdateCommit(ctx context.Context, repository, hash string, o model.Commit) error {
+	err := a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.One(ctx, updateCommitQuery, repository, hash, o.Hash, o.Type, o.Component, o.Revert, o.Breaking, o.Content, o.Date.Unix(), o.Remote, o.Repository, arrayValue(o.Paths), arrayValue(model.ReferenceKeys(o.References)))
+	})
+
+	if isUniqueViolation(err) {
+		return httpModel.WrapInvalid(fmt.Errorf("commit `%s` of `%s` already exists", o.Hash, o.Repository))
+	}
+
+	return err
+}
+
+const deleteCommitQuery = `
+DELETE FROM
+  herodote.commit
+WHERE
+  repository = $1
+  AND hash = $2
+RETURNING
+  hash
 `
 
-func (a App) SaveCommit(ctx context.Context, o model.Commit) error {
+func (a App) DeleteCommit(ctx context.Context, repository, hash string) error {
 	return a.db.DoAtomic(ctx, func(ctx context.Context) error {
-		return a.db.Exec(ctx, insertCommitQuery, o.Hash, o.Type, o.Component, o.Revert, o.Breaking, o.Content, o.Date.Unix(), o.Remote, o.Repository)
+		return a.db.Get(ctx, func(row pgx.Row) error {
+			var deleted string
+
+			err := row.Scan(&deleted)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return httpModel.WrapNotFound(fmt.Errorf("commit `%s` of `%s` not found", hash, repository))
+			}
+
+			return err
+		}, deleteCommitQuery, repository, hash)
+	})
+}
+
+const deleteRepositoryDeploymentsQuery = `
+DELETE FROM
+  herodote.deployment
+WHERE
+  repository = $1
+`
+
+const deleteRepositoryQuery = `
+WITH deleted AS (
+  DELETE FROM
+    herodote.commit
+  WHERE
+    repository = $1
+  RETURNING
+    hash
+) SELECT
+  count(1)
+FROM
+  deleted
+`
+
+func (a App) DeleteRepository(ctx context.Context, repository string) (uint64, error) {
+	var count uint64
+
+	err := a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		if err := a.db.Exec(ctx, deleteRepositoryDeploymentsQuery, repository); err != nil {
+			return err
+		}
+
+		return a.db.Get(ctx, func(row pgx.Row) error {
+			return row.Scan(&count)
+		}, deleteRepositoryQuery, repository)
 	})
+
+	return count, err
 }
 
 const refreshFiltersQuery = `REFRESH MATERIALIZED VIEW herodote.filters`
@@ -62,3 +339,49 @@ func (a App) Refresh(ctx context.Context) error {
 		return a.db.Exec(ctx, refreshFiltersQuery)
 	})
 }
+
+const rewriteComponentQuery = `
+WITH rewritten AS (
+  UPDATE
+    herodote.commit
+  SET
+    component = $2,
+    search_vector = to_tsvector('english', hash) || to_tsvector('english', type) || to_tsvector('english', $2) || to_tsvector('english', content)
+  WHERE
+    component = $1
+  RETURNING
+    repository
+) SELECT
+  repository,
+  count(1)
+FROM
+  rewritten
+GROUP BY
+  repository
+`
+
+// RewriteComponent replaces a component value in every commit, it returns the count of rewritten commits and their repositories
+func (a App) RewriteComponent(ctx context.Context, from, to string) (uint64, []string, error) {
+	var count uint64
+	var repositories []string
+
+	scanner := func(rows pgx.Rows) error {
+		var repository string
+		var repositoryCount uint64
+
+		if err := rows.Scan(&repository, &repositoryCount); err != nil {
+			return err
+		}
+
+		count += repositoryCount
+		repositories = append(repositories, repository)
+
+		return nil
+	}
+
+	err := a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.List(ctx, scanner, rewriteComponentQuery, from, to)
+	})
+
+	return count, repositories, err
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const listCommitEventsQuery = `
+SELECT
+  id,
+  hash,
+  type,
+  component,
+  revert,
+  breaking,
+  content,
+  date,
+  remote,
+  repository,
+  paths,
+  refs,
+  picked_from,
+` + commitBranchesColumn + `
+FROM
+  herodote.commit AS c
+WHERE
+  id > $1
+ORDER BY
+  id ASC
+LIMIT $2
+`
+
+// ListCommitEvents returns commits saved after the given id, in the order of saving
+func (a App) ListCommitEvents(ctx context.Context, after uint64, pageSize uint) ([]model.CommitEvent, error) {
+	var list []model.CommitEvent
+
+	scanner := func(rows pgx.Rows) error {
+		var item model.CommitEvent
+		var references []string
+
+		if err := rows.Scan(&item.ID, &item.Commit.Hash, &item.Commit.Type, &item.Commit.Component, &item.Commit.Revert, &item.Commit.Breaking, &item.Commit.Content, &item.Commit.Date, &item.Commit.Remote, &item.Commit.Repository, &item.Commit.Paths, &references, &item.Commit.PickedFrom, &item.Commit.Branches); err != nil {
+			return err
+		}
+
+		item.Commit.References = model.NewReferences(references)
+
+		list = append(list, item)
+		return nil
+	}
+
+	err := a.db.List(ctx, scanner, listCommitEventsQuery, after, pageSize)
+
+	return list, err
+}
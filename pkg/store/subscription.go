@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const listSubscriptionsQuery = `
+SELECT
+  id,
+  owner,
+  email,
+  frequency,
+  filter,
+  tenant,
+  creation_date,
+  last_sent
+FROM
+  herodote.subscription
+WHERE
+  $1 = ''
+  OR owner = $1
+ORDER BY
+  id ASC
+`
+
+// ListSubscriptions returns subscriptions of the owner, or all of them if owner is empty
+func (a App) ListSubscriptions(ctx context.Context, owner string) ([]model.Subscription, error) {
+	var list []model.Subscription
+
+	scanner := func(rows pgx.Rows) error {
+		var item model.Subscription
+		if err := rows.Scan(&item.ID, &item.Owner, &item.Email, &item.Frequency, &item.Filter, &item.Tenant, &item.CreationDate, &item.LastSent); err != nil {
+			return err
+		}
+
+		list = append(list, item)
+		return nil
+	}
+
+	err := a.db.List(ctx, scanner, listSubscriptionsQuery, owner)
+
+	return list, err
+}
+
+const insertSubscriptionQuery = `
+INSERT INTO
+  herodote.subscription
+(
+  owner,
+  email,
+  frequency,
+  filter,
+  tenant
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5
+) RETURNING
+  id
+`
+
+func (a App) CreateSubscription(ctx context.Context, subscription model.Subscription) (uint64, error) {
+	var id uint64
+
+	err := a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.Get(ctx, func(row pgx.Row) error {
+			return row.Scan(&id)
+		}, insertSubscriptionQuery, subscription.Owner, subscription.Email, subscription.Frequency, subscription.Filter, subscription.Tenant)
+	})
+
+	return id, err
+}
+
+const deleteSubscriptionQuery = `
+DELETE FROM
+  herodote.subscription
+WHERE
+  id = $1
+  AND ($2 = '' OR owner = $2)
+RETURNING
+  id
+`
+
+// DeleteSubscription deletes the subscription of the owner, or of anyone if owner is empty
+func (a App) DeleteSubscription(ctx context.Context, id uint64, owner string) error {
+	return a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.Get(ctx, func(row pgx.Row) error {
+			var deleted uint64
+
+			err := row.Scan(&deleted)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return httpModel.WrapNotFound(fmt.Errorf("subscription `%d` not found", id))
+			}
+
+			return err
+		}, deleteSubscriptionQuery, id, owner)
+	})
+}
+
+const markSubscriptionSentQuery = `
+UPDATE
+  herodote.subscription
+SET
+  last_sent = $2
+WHERE
+  id = $1
+`
+
+func (a App) MarkSubscriptionSent(ctx context.Context, id uint64, sent time.Time) error {
+	return a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.Exec(ctx, markSubscriptionSentQuery, id, sent)
+	})
+}
+
+const listCommitsQuery = `
+SELECT
+  hash,
+  type,
+  component,
+  revert,
+  breaking,
+  content,
+  date,
+  remote,
+  repository,
+  paths,
+  refs
+FROM
+  herodote.commit
+WHERE
+  date >= $1
+  AND date < $2
+ORDER BY
+  date DESC
+`
+
+// ListCommits returns commits of the period, most recent first
+func (a App) ListCommits(ctx context.Context, since, until time.Time) ([]model.Commit, error) {
+	var list []model.Commit
+
+	scanner := func(rows pgx.Rows) error {
+		var item model.Commit
+		var references []string
+
+		if err := rows.Scan(&item.Hash, &item.Type, &item.Component, &item.Revert, &item.Breaking, &item.Content, &item.Date, &item.Remote, &item.Repository, &item.Paths, &references); err != nil {
+			return err
+		}
+
+		item.References = model.NewReferences(references)
+
+		list = append(list, item)
+		return nil
+	}
+
+	err := a.db.List(ctx, scanner, listCommitsQuery, since, until)
+
+	return list, err
+}
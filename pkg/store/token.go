@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const listTokensQuery = `
+SELECT
+  name,
+  patterns,
+  tenant,
+  creation_date,
+  last_used
+FROM
+  herodote.token
+ORDER BY
+  name ASC
+`
+
+func (a App) ListTokens(ctx context.Context) ([]model.Token, error) {
+	var list []model.Token
+
+	scanner := func(rows pgx.Rows) error {
+		var item model.Token
+		if err := rows.Scan(&item.Name, &item.Patterns, &item.Tenant, &item.CreationDate, &item.LastUsed); err != nil {
+			return err
+		}
+
+		list = append(list, item)
+		return nil
+	}
+
+	err := a.db.List(ctx, scanner, listTokensQuery)
+
+	return list, err
+}
+
+const getTokenByHashQuery = `
+SELECT
+  name,
+  hash,
+  patterns,
+  tenant,
+  creation_date,
+  last_used
+FROM
+  herodote.token
+WHERE
+  hash = $1
+`
+
+// GetTokenByHash returns the token and its hash, hash is empty if not found
+func (a App) GetTokenByHash(ctx context.Context, hash string) (model.Token, string, error) {
+	var item model.Token
+	var storedHash string
+
+	scanner := func(row pgx.Row) error {
+		err := row.Scan(&item.Name, &storedHash, &item.Patterns, &item.Tenant, &item.CreationDate, &item.LastUsed)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		return err
+	}
+
+	err := a.db.Get(ctx, scanner, getTokenByHashQuery, hash)
+
+	return item, storedHash, err
+}
+
+const insertTokenQuery = `
+INSERT INTO
+  herodote.token
+(
+  name,
+  hash,
+  patterns,
+  tenant
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4
+)
+`
+
+func (a App) CreateToken(ctx context.Context, token model.Token, hash string) error {
+	err := a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.One(ctx, insertTokenQuery, token.Name, hash, token.Patterns, token.Tenant)
+	})
+
+	if isUniqueViolation(err) {
+		return httpModel.WrapInvalid(fmt.Errorf("token `%s` already exists", token.Name))
+	}
+
+	return err
+}
+
+const deleteTokenQuery = `
+DELETE FROM
+  herodote.token
+WHERE
+  name = $1
+RETURNING
+  name
+`
+
+func (a App) DeleteToken(ctx context.Context, name string) error {
+	return a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.Get(ctx, func(row pgx.Row) error {
+			var deleted string
+
+			err := row.Scan(&deleted)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return httpModel.WrapNotFound(fmt.Errorf("token `%s` not found", name))
+			}
+
+			return err
+		}, deleteTokenQuery, name)
+	})
+}
+
+const touchTokenQuery = `
+UPDATE
+  herodote.token
+SET
+  last_used = now()
+WHERE
+  name = $1
+  AND (last_used IS NULL OR last_used < now() - interval '1 minute')
+`
+
+func (a App) TouchToken(ctx context.Context, name string) error {
+	return a.db.Exec(ctx, touchTokenQuery, name)
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const listWebhooksQuery = `
+SELECT
+  name,
+  url,
+  secret,
+  filter,
+  creation_date
+FROM
+  herodote.webhook
+ORDER BY
+  name ASC
+`
+
+// ListWebhooks returns webhooks with their secret, needed for signing deliveries
+func (a App) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	var list []model.Webhook
+
+	scanner := func(rows pgx.Rows) error {
+		var item model.Webhook
+		if err := rows.Scan(&item.Name, &item.URL, &item.Secret, &item.Filter, &item.CreationDate); err != nil {
+			return err
+		}
+
+		list = append(list, item)
+		return nil
+	}
+
+	err := a.db.List(ctx, scanner, listWebhooksQuery)
+
+	return list, err
+}
+
+const insertWebhookQuery = `
+INSERT INTO
+  herodote.webhook
+(
+  name,
+  url,
+  secret,
+  filter
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4
+)
+`
+
+func (a App) CreateWebhook(ctx context.Context, webhook model.Webhook) error {
+	err := a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.One(ctx, insertWebhookQuery, webhook.Name, webhook.URL, webhook.Secret, webhook.Filter)
+	})
+
+	if isUniqueViolation(err) {
+		return httpModel.WrapInvalid(fmt.Errorf("webhook `%s` already exists", webhook.Name))
+	}
+
+	return err
+}
+
+const deleteWebhookQuery = `
+DELETE FROM
+  herodote.webhook
+WHERE
+  name = $1
+RETURNING
+  name
+`
+
+func (a App) DeleteWebhook(ctx context.Context, name string) error {
+	return a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.Get(ctx, func(row pgx.Row) error {
+			var deleted string
+
+			err := row.Scan(&deleted)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return httpModel.WrapNotFound(fmt.Errorf("webhook `%s` not found", name))
+			}
+
+			return err
+		}, deleteWebhookQuery, name)
+	})
+}
+
+const listWebhookFailuresQuery = `
+SELECT
+  webhook,
+  payload,
+  error,
+  attempts,
+  creation_date
+FROM
+  herodote.webhook_failure
+WHERE
+  webhook = $1
+ORDER BY
+  creation_date DESC
+LIMIT $2
+`
+
+func (a App) ListWebhookFailures(ctx context.Context, name string, pageSize uint) ([]model.WebhookFailure, error) {
+	var list []model.WebhookFailure
+
+	scanner := func(rows pgx.Rows) error {
+		var item model.WebhookFailure
+		if err := rows.Scan(&item.Webhook, &item.Payload, &item.Error, &item.Attempts, &item.CreationDate); err != nil {
+			return err
+		}
+
+		list = append(list, item)
+		return nil
+	}
+
+	err := a.db.List(ctx, scanner, listWebhookFailuresQuery, name, pageSize)
+
+	return list, err
+}
+
+const insertWebhookFailureQuery = `
+INSERT INTO
+  herodote.webhook_failure
+(
+  webhook,
+  payload,
+  error,
+  attempts
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4
+)
+`
+
+func (a App) SaveWebhookFailure(ctx context.Context, failure model.WebhookFailure) error {
+	return a.db.DoAtomic(ctx, func(ctx context.Context) error {
+		return a.db.Exec(ctx, insertWebhookFailureQuery, failure.Webhook, failure.Payload, failure.Error, failure.Attempts)
+	})
+}
@@ -0,0 +1,58 @@
+package vocabulary
+
+import (
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+const otherSection = "Others"
+
+// Changelog groups commits by section of their type, in the order of types, commits of unknown types are grouped last.
+// A section is a production change if one of its types is.
+func (a App) Changelog(commits []model.Commit) []model.ChangelogSection {
+	var sections []model.ChangelogSection
+	indexes := make(map[string]int)
+
+	sectionOf := make(map[string]string, len(a.types))
+	for _, item := range a.types {
+		title := item.Section
+		if len(title) == 0 {
+			title = item.Name
+		}
+
+		sectionOf[item.Name] = title
+
+		if _, ok := indexes[title]; !ok {
+			indexes[title] = len(sections)
+			sections = append(sections, model.ChangelogSection{Title: title})
+		}
+
+		if item.Production {
+			sections[indexes[title]].Production = true
+		}
+	}
+
+	var others []model.Commit
+
+	for _, commit := range commits {
+		title, ok := sectionOf[commit.Type]
+		if !ok {
+			others = append(others, commit)
+			continue
+		}
+
+		sections[indexes[title]].Commits = append(sections[indexes[title]].Commits, commit)
+	}
+
+	if len(others) != 0 {
+		sections = append(sections, model.ChangelogSection{Title: otherSection, Commits: others})
+	}
+
+	var output []model.ChangelogSection
+	for _, section := range sections {
+		if len(section.Commits) != 0 {
+			output = append(output, section)
+		}
+	}
+
+	return output
+}
@@ -0,0 +1,61 @@
+package vocabulary
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+func TestChangelog(t *testing.T) {
+	feat := model.Commit{Hash: "1", Type: "feat"}
+	fix := model.Commit{Hash: "2", Type: "fix"}
+	otherFeat := model.Commit{Hash: "3", Type: "feat"}
+	unknown := model.Commit{Hash: "4", Type: "wip"}
+
+	cases := map[string]struct {
+		types []model.Type
+		input []model.Commit
+		want  []model.ChangelogSection
+	}{
+		"empty": {
+			defaultTypes,
+			nil,
+			nil,
+		},
+		"order of types": {
+			defaultTypes,
+			[]model.Commit{fix, feat, unknown, otherFeat},
+			[]model.ChangelogSection{
+				{Title: "Features", Commits: []model.Commit{feat, otherFeat}, Production: true},
+				{Title: "Bug fixes", Commits: []model.Commit{fix}, Production: true},
+				{Title: otherSection, Commits: []model.Commit{unknown}},
+			},
+		},
+		"shared section": {
+			[]model.Type{
+				{Name: "feat", Section: "Changes"},
+				{Name: "fix", Section: "Changes", Production: true},
+				{Name: "chore"},
+			},
+			[]model.Commit{fix, feat, {Hash: "5", Type: "chore"}},
+			[]model.ChangelogSection{
+				{Title: "Changes", Commits: []model.Commit{fix, feat}, Production: true},
+				{Title: "chore", Commits: []model.Commit{{Hash: "5", Type: "chore"}}},
+			},
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			instance, err := newApp(tc.types, unknownAccept)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := instance.Changelog(tc.input); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Changelog() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
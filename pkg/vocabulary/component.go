@@ -0,0 +1,88 @@
+package vocabulary
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+type componentPattern struct {
+	pattern *regexp.Regexp
+	name    string
+}
+
+// componentRules normalizes components: exact aliases first, then patterns in declaration order
+type componentRules struct {
+	aliases    map[string]string
+	patterns   []componentPattern
+	components []model.Component
+}
+
+func newComponentRules(components []model.Component) (componentRules, error) {
+	rules := componentRules{
+		aliases: make(map[string]string),
+	}
+
+	for _, item := range components {
+		item = item.Sanitize()
+		if err := item.Check(); err != nil {
+			return componentRules{}, err
+		}
+
+		rules.components = append(rules.components, item)
+
+		for _, alias := range append([]string{item.Name}, item.Aliases...) {
+			if existing, ok := rules.aliases[alias]; ok && existing != item.Name {
+				return componentRules{}, fmt.Errorf("component `%s` is declared by `%s` and `%s`", alias, existing, item.Name)
+			}
+
+			rules.aliases[alias] = item.Name
+		}
+
+		for _, pattern := range item.Patterns {
+			rules.patterns = append(rules.patterns, componentPattern{
+				pattern: regexp.MustCompile(pattern),
+				name:    item.Name,
+			})
+		}
+	}
+
+	return rules, nil
+}
+
+func (a App) Components() []model.Component {
+	return a.components.components
+}
+
+// ResolveComponent returns the canonical name of a component, unknown components are kept as is
+func (a App) ResolveComponent(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) == 0 {
+		return name
+	}
+
+	if canonical, ok := a.components.aliases[name]; ok {
+		return canonical
+	}
+
+	for _, item := range a.components.patterns {
+		if item.pattern.MatchString(name) {
+			return item.name
+		}
+	}
+
+	return name
+}
+
+// ResolveComponents returns canonical names of components used for filtering
+func (a App) ResolveComponents(names []string) []string {
+	output := make([]string, len(names))
+
+	for index, name := range names {
+		output[index] = a.ResolveComponent(name)
+	}
+
+	return output
+}
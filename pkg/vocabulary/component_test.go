@@ -0,0 +1,57 @@
+package vocabulary
+
+import (
+	"testing"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+func TestResolveComponent(t *testing.T) {
+	rules, err := newComponentRules([]model.Component{
+		{Name: "api", Aliases: []string{"apis", "HTTP-API"}, Patterns: []string{"^api-v[0-9]+$"}},
+		{Name: "ui", Patterns: []string{"^front"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance := App{components: rules}
+
+	cases := map[string]struct {
+		input string
+		want  string
+	}{
+		"empty": {
+			"",
+			"",
+		},
+		"canonical": {
+			"api",
+			"api",
+		},
+		"alias": {
+			" Http-Api ",
+			"api",
+		},
+		"pattern": {
+			"api-v2",
+			"api",
+		},
+		"other pattern": {
+			"frontend",
+			"ui",
+		},
+		"unknown": {
+			"store",
+			"store",
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := instance.ResolveComponent(tc.input); got != tc.want {
+				t.Errorf("ResolveComponent() = `%s`, want `%s`", got, tc.want)
+			}
+		})
+	}
+}
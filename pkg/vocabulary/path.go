@@ -0,0 +1,68 @@
+package vocabulary
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+type pathRule struct {
+	pattern    *regexp.Regexp
+	repository string
+	component  string
+}
+
+func newPathRules(rules []model.PathRule) ([]pathRule, error) {
+	output := make([]pathRule, 0, len(rules))
+
+	for _, rule := range rules {
+		rule = rule.Sanitize()
+		if err := rule.Check(); err != nil {
+			return nil, err
+		}
+
+		output = append(output, pathRule{
+			repository: rule.Repository,
+			pattern:    regexp.MustCompile(rule.Pattern),
+			component:  rule.Component,
+		})
+	}
+
+	return output, nil
+}
+
+// InferComponent returns the component of a commit from its changed paths, empty if no rule matches or if paths belong to several components
+func (a App) InferComponent(repository string, paths []string) string {
+	var component string
+
+	for _, changed := range paths {
+		found := a.pathComponent(repository, changed)
+		if len(found) == 0 {
+			continue
+		}
+
+		if len(component) != 0 && component != found {
+			return ""
+		}
+
+		component = found
+	}
+
+	return component
+}
+
+func (a App) pathComponent(repository, changed string) string {
+	for _, rule := range a.paths {
+		if matched, _ := path.Match(rule.repository, repository); !matched {
+			continue
+		}
+
+		if match := rule.pattern.FindStringSubmatchIndex(changed); match != nil {
+			return strings.ToLower(string(rule.pattern.ExpandString(nil, rule.component, changed, match)))
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,64 @@
+package vocabulary
+
+import (
+	"testing"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+func TestInferComponent(t *testing.T) {
+	rules, err := newPathRules([]model.PathRule{
+		{Repository: "vibioh/monorepo", Pattern: "^services/([^/]+)/", Component: "$1"},
+		{Repository: "vibioh/*", Pattern: "^web/", Component: "UI"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance := App{paths: rules}
+
+	cases := map[string]struct {
+		repository string
+		paths      []string
+		want       string
+	}{
+		"no path": {
+			"vibioh/monorepo",
+			nil,
+			"",
+		},
+		"capture": {
+			"vibioh/monorepo",
+			[]string{"services/billing/main.go", "go.mod"},
+			"billing",
+		},
+		"same component": {
+			"vibioh/monorepo",
+			[]string{"services/billing/main.go", "services/billing/README.md"},
+			"billing",
+		},
+		"several components": {
+			"vibioh/monorepo",
+			[]string{"services/billing/main.go", "services/auth/main.go"},
+			"",
+		},
+		"other repository": {
+			"vibioh/herodote",
+			[]string{"services/billing/main.go"},
+			"",
+		},
+		"wildcard repository": {
+			"vibioh/herodote",
+			[]string{"web/index.html"},
+			"ui",
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := instance.InferComponent(tc.repository, tc.paths); got != tc.want {
+				t.Errorf("InferComponent() = `%s`, want `%s`", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package vocabulary
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+var (
+	issueRegex = regexp.MustCompile(`(?:^|[^\w&])(#\d+)\b`)
+
+	defaultTrackers = []model.Tracker{
+		{Remote: "github.com", Prefix: model.IssuePrefix, URL: "https://github.com/{repository}/issues/{id}"},
+		{Remote: "gitlab.com", Prefix: model.IssuePrefix, URL: "https://gitlab.com/{repository}/-/issues/{id}"},
+	}
+)
+
+type trackers struct {
+	keyRegex *regexp.Regexp
+	trackers []model.Tracker
+}
+
+// newTrackers compiles trackers, keys other than issues are only extracted for declared prefixes, to avoid matching `UTF-8` or `SHA-256`
+func newTrackers(items []model.Tracker) (trackers, error) {
+	output := trackers{}
+
+	var prefixes []string
+
+	for _, item := range items {
+		item = item.Sanitize()
+		if err := item.Check(); err != nil {
+			return trackers{}, err
+		}
+
+		output.trackers = append(output.trackers, item)
+
+		if item.Prefix != model.IssuePrefix {
+			prefixes = append(prefixes, regexp.QuoteMeta(item.Prefix))
+		}
+	}
+
+	if len(prefixes) != 0 {
+		output.keyRegex = regexp.MustCompile(`\b((?:` + strings.Join(prefixes, "|") + `)\d+)\b`)
+	}
+
+	return output, nil
+}
+
+// ExtractReferences returns the sorted and deduplicated references found in a commit's content
+func (a App) ExtractReferences(content string) []model.Reference {
+	keys := make(map[string]struct{})
+
+	for _, match := range issueRegex.FindAllStringSubmatch(content, -1) {
+		keys[match[1]] = struct{}{}
+	}
+
+	if a.trackers.keyRegex != nil {
+		for _, match := range a.trackers.keyRegex.FindAllStringSubmatch(strings.ToUpper(content), -1) {
+			keys[match[1]] = struct{}{}
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	output := make([]string, 0, len(keys))
+	for key := range keys {
+		output = append(output, key)
+	}
+
+	sort.Strings(output)
+
+	return model.NewReferences(output)
+}
+
+// LinkReferences returns a copy of commits with URL of references set by the first matching tracker, given commits may be shared by the cache
+func (a App) LinkReferences(commits []model.Commit) []model.Commit {
+	output := make([]model.Commit, len(commits))
+
+	for index, commit := range commits {
+		output[index] = a.LinkReference(commit)
+	}
+
+	return output
+}
+
+// LinkReference returns a copy of the commit with URL of references set by the first matching tracker
+func (a App) LinkReference(commit model.Commit) model.Commit {
+	if len(commit.References) == 0 {
+		return commit
+	}
+
+	references := make([]model.Reference, len(commit.References))
+
+	for index, reference := range commit.References {
+		references[index] = reference
+
+		for _, tracker := range a.trackers.trackers {
+			if tracker.Matches(commit, reference.Key) {
+				references[index].URL = tracker.Link(commit, reference.Key)
+				break
+			}
+		}
+	}
+
+	commit.References = references
+
+	return commit
+}
@@ -0,0 +1,92 @@
+package vocabulary
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+func TestExtractReferences(t *testing.T) {
+	trackersList, err := newTrackers(append([]model.Tracker{{Prefix: "plat-", URL: "https://jira.example.com/browse/{key}"}}, defaultTrackers...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance := App{trackers: trackersList}
+
+	cases := map[string]struct {
+		content string
+		want    []model.Reference
+	}{
+		"none": {
+			"Add README.md",
+			nil,
+		},
+		"squash suffix": {
+			"Add README.md (#456)",
+			[]model.Reference{{Key: "#456"}},
+		},
+		"issue and ticket": {
+			"PLAT-789 fix #123 and #123 again",
+			[]model.Reference{{Key: "#123"}, {Key: "PLAT-789"}},
+		},
+		"undeclared prefix": {
+			"Encode in UTF-8 with SHA-256",
+			nil,
+		},
+		"html entity": {
+			"Don&#39;t panic",
+			nil,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			if got := instance.ExtractReferences(tc.content); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ExtractReferences() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLinkReference(t *testing.T) {
+	trackersList, err := newTrackers(append([]model.Tracker{{Prefix: "PLAT-", URL: "https://jira.example.com/browse/{key}"}}, defaultTrackers...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance := App{trackers: trackersList}
+
+	cases := map[string]struct {
+		commit model.Commit
+		want   []model.Reference
+	}{
+		"github": {
+			model.Commit{Remote: "github.com", Repository: "vibioh/herodote", References: []model.Reference{{Key: "#123"}}},
+			[]model.Reference{{Key: "#123", URL: "https://github.com/vibioh/herodote/issues/123"}},
+		},
+		"unknown remote": {
+			model.Commit{Remote: "git.example.com", Repository: "vibioh/herodote", References: []model.Reference{{Key: "#123"}}},
+			[]model.Reference{{Key: "#123"}},
+		},
+		"tracker": {
+			model.Commit{Remote: "git.example.com", Repository: "vibioh/herodote", References: []model.Reference{{Key: "PLAT-789"}}},
+			[]model.Reference{{Key: "PLAT-789", URL: "https://jira.example.com/browse/PLAT-789"}},
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			original := append([]model.Reference(nil), tc.commit.References...)
+
+			if got := instance.LinkReference(tc.commit); !reflect.DeepEqual(got.References, tc.want) {
+				t.Errorf("LinkReference() = %+v, want %+v", got.References, tc.want)
+			}
+
+			if !reflect.DeepEqual(tc.commit.References, original) {
+				t.Errorf("LinkReference() modified the given commit: %+v", tc.commit.References)
+			}
+		})
+	}
+}
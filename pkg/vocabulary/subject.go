@@ -0,0 +1,66 @@
+package vocabulary
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+const mergeType = "merge"
+
+var (
+	conventionalRegex = regexp.MustCompile(`^(?i)(revert: )?(\w+)(?:\((.+)\))?(!)?: (.+)$`)
+	mergeRegex        = regexp.MustCompile(`Merge (?:pull request|branch)`)
+	cherryPickRegex   = regexp.MustCompile(`(?m)^[ \t]*\(cherry picked from commit ([0-9a-fA-F]{7,40})\)[ \t]*$`)
+)
+
+// ParseSubject reads a conventional commit subject, e.g. `feat(api)!: Add export`, only declared types are recognized.
+// Merges of branches or pull requests have the `merge` type, false is returned for other subjects.
+func (a App) ParseSubject(subject string) (model.Commit, bool) {
+	subject = strings.TrimSpace(subject)
+
+	if matches := conventionalRegex.FindStringSubmatch(subject); matches != nil {
+		if _, ok := a.aliases[strings.ToLower(matches[2])]; ok {
+			return model.Commit{
+				Revert:    len(matches[1]) != 0,
+				Type:      matches[2],
+				Component: matches[3],
+				Breaking:  len(matches[4]) != 0,
+				Content:   matches[5],
+			}, true
+		}
+	}
+
+	if mergeRegex.MatchString(subject) {
+		return model.Commit{
+			Type:    mergeType,
+			Content: subject,
+		}, true
+	}
+
+	return model.Commit{}, false
+}
+
+// ExtractCherryPick returns the content without the trailer added by `git cherry-pick -x`, and the hash of the picked commit, empty if none
+func (a App) ExtractCherryPick(content string) (string, string) {
+	matches := cherryPickRegex.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content, ""
+	}
+
+	// a pick of a pick has a trailer by pick, the first one is the original commit
+	hash := strings.ToLower(content[matches[0][2]:matches[0][3]])
+
+	var output strings.Builder
+	previous := 0
+
+	for _, match := range matches {
+		output.WriteString(content[previous:match[0]])
+		previous = match[1]
+	}
+
+	output.WriteString(content[previous:])
+
+	return strings.TrimSpace(output.String()), hash
+}
@@ -0,0 +1,106 @@
+package vocabulary
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+func TestParseSubject(t *testing.T) {
+	app, err := newApp(defaultTypes, unknownAccept)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		subject string
+		want    model.Commit
+		wantOk  bool
+	}{
+		"simple": {
+			"feat: Add import",
+			model.Commit{Type: "feat", Content: "Add import"},
+			true,
+		},
+		"component": {
+			"Fix(api): Handle empty dump",
+			model.Commit{Type: "Fix", Component: "api", Content: "Handle empty dump"},
+			true,
+		},
+		"alias": {
+			"feature: Add import",
+			model.Commit{Type: "feature", Content: "Add import"},
+			true,
+		},
+		"breaking revert": {
+			"revert: refactor(store)!: Rename table",
+			model.Commit{Type: "refactor", Component: "store", Content: "Rename table", Breaking: true, Revert: true},
+			true,
+		},
+		"merge": {
+			"Merge pull request #12 from vibioh/import",
+			model.Commit{Type: "merge", Content: "Merge pull request #12 from vibioh/import"},
+			true,
+		},
+		"unknown type": {
+			"wip: Something",
+			model.Commit{},
+			false,
+		},
+		"not conventional": {
+			"Update README.md",
+			model.Commit{},
+			false,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			got, ok := app.ParseSubject(tc.subject)
+
+			if ok != tc.wantOk || !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ParseSubject() = (%+v, %t), want (%+v, %t)", got, ok, tc.want, tc.wantOk)
+			}
+		})
+	}
+}
+
+func TestExtractCherryPick(t *testing.T) {
+	cases := map[string]struct {
+		content     string
+		want        string
+		wantPicking string
+	}{
+		"none": {
+			"Fix release",
+			"Fix release",
+			"",
+		},
+		"trailer": {
+			"Fix release\n\n(cherry picked from commit 1A2BC34D5E6F7A8B9C0D1E2F3A4B5C6D7E8F9A0B)",
+			"Fix release",
+			"1a2bc34d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b",
+		},
+		"picked twice": {
+			"Fix release\n\n(cherry picked from commit 1a2bc34d)\n(cherry picked from commit 5e6f7a8b)\n",
+			"Fix release",
+			"1a2bc34d",
+		},
+		"not a trailer": {
+			"Explain (cherry picked from commit 1a2bc34d) in docs",
+			"Explain (cherry picked from commit 1a2bc34d) in docs",
+			"",
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			got, gotPicking := App{}.ExtractCherryPick(tc.content)
+
+			if got != tc.want || gotPicking != tc.wantPicking {
+				t.Errorf("ExtractCherryPick() = (`%s`, `%s`), want (`%s`, `%s`)", got, gotPicking, tc.want, tc.wantPicking)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package vocabulary
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/herodote/pkg/model"
+	httpModel "github.com/ViBiOh/httputils/v4/pkg/model"
+)
+
+const (
+	unknownAccept = "accept"
+	unknownReject = "reject"
+)
+
+var defaultTypes = []model.Type{
+	{Name: "feat", Description: "A new feature for user", Color: "limegreen", Section: "Features", Aliases: []string{"feature"}, Production: true},
+	{Name: "fix", Description: "A bug fix for user", Color: "salmon", Section: "Bug fixes", Aliases: []string{"bugfix"}, Production: true},
+	{Name: "perf", Description: "A performance improvement for user", Color: "orange", Section: "Performance improvements", Production: true},
+	{Name: "refactor", Description: "A change that is not a feature not a bug", Color: "cornflowerblue", Section: "Refactoring", Production: true},
+	{Name: "build", Description: "Changes that affect the build system or external dependencies", Color: "peachpuff", Section: "Build"},
+	{Name: "ci", Description: "Changes in Continuous Integration configuration files and scripts", Color: "peachpuff", Section: "Continuous integration"},
+	{Name: "chore", Description: "Changes in the core of the repository", Color: "silver", Section: "Chores"},
+	{Name: "docs", Description: "Documentation only changes", Color: "silver", Section: "Documentation", Aliases: []string{"doc"}},
+	{Name: "style", Description: "A change that do not affect the meaning of the code", Color: "silver", Section: "Style"},
+	{Name: "test", Description: "A new test or correcting existing tests", Color: "aquamarine", Section: "Tests", Aliases: []string{"tests"}},
+	{Name: "merge", Description: "A merge of a branch or a pull request", Color: "silver", Section: "Merges"},
+}
+
+type App struct {
+	aliases    map[string]string
+	components componentRules
+	paths      []pathRule
+	trackers   trackers
+	unknown    string
+	types      []model.Type
+}
+
+type Config struct {
+	typesFile      *string
+	unknown        *string
+	componentsFile *string
+	pathsFile      *string
+	trackersFile   *string
+}
+
+func Flags(fs *flag.FlagSet, prefix string) Config {
+	return Config{
+		typesFile:      flags.New("TypesFile", "Path of a JSON file describing commit types, default vocabulary if empty").Prefix(prefix).DocPrefix("vocabulary").String(fs, "", nil),
+		unknown:        flags.New("TypesUnknown", "Behavior for unknown commit types: accept, reject or name of the type to remap to").Prefix(prefix).DocPrefix("vocabulary").String(fs, unknownAccept, nil),
+		componentsFile: flags.New("ComponentsFile", "Path of a JSON file describing components aliases and patterns").Prefix(prefix).DocPrefix("vocabulary").String(fs, "", nil),
+		pathsFile:      flags.New("PathsFile", "Path of a JSON file describing per-repository changed path to component rules").Prefix(prefix).DocPrefix("vocabulary").String(fs, "", nil),
+		trackersFile:   flags.New("TrackersFile", "Path of a JSON file describing issue trackers links, GitHub and GitLab issues if empty").Prefix(prefix).DocPrefix("vocabulary").String(fs, "", nil),
+	}
+}
+
+func New(config Config) (App, error) {
+	types := defaultTypes
+
+	if typesFile := strings.TrimSpace(*config.typesFile); len(typesFile) != 0 {
+		if err := loadFile(typesFile, &types); err != nil {
+			return App{}, fmt.Errorf("load types: %w", err)
+		}
+	}
+
+	app, err := newApp(types, strings.ToLower(strings.TrimSpace(*config.unknown)))
+	if err != nil {
+		return app, err
+	}
+
+	if componentsFile := strings.TrimSpace(*config.componentsFile); len(componentsFile) != 0 {
+		var components []model.Component
+		if err = loadFile(componentsFile, &components); err != nil {
+			return App{}, fmt.Errorf("load components: %w", err)
+		}
+
+		if app.components, err = newComponentRules(components); err != nil {
+			return App{}, err
+		}
+	}
+
+	if pathsFile := strings.TrimSpace(*config.pathsFile); len(pathsFile) != 0 {
+		var rules []model.PathRule
+		if err = loadFile(pathsFile, &rules); err != nil {
+			return App{}, fmt.Errorf("load paths: %w", err)
+		}
+
+		if app.paths, err = newPathRules(rules); err != nil {
+			return App{}, err
+		}
+	}
+
+	trackersList := defaultTrackers
+
+	if trackersFile := strings.TrimSpace(*config.trackersFile); len(trackersFile) != 0 {
+		if err = loadFile(trackersFile, &trackersList); err != nil {
+			return App{}, fmt.Errorf("load trackers: %w", err)
+		}
+	}
+
+	if app.trackers, err = newTrackers(trackersList); err != nil {
+		return App{}, err
+	}
+
+	return app, nil
+}
+
+func newApp(types []model.Type, unknown string) (App, error) {
+	app := App{
+		unknown: unknown,
+		aliases: make(map[string]string),
+	}
+
+	for _, item := range types {
+		item = item.Sanitize()
+		if err := item.Check(); err != nil {
+			return App{}, err
+		}
+
+		app.types = append(app.types, item)
+
+		for _, alias := range append([]string{item.Name}, item.Aliases...) {
+			if existing, ok := app.aliases[alias]; ok {
+				return App{}, fmt.Errorf("type `%s` is declared by `%s` and `%s`", alias, existing, item.Name)
+			}
+
+			app.aliases[alias] = item.Name
+		}
+	}
+
+	if unknown != unknownAccept && unknown != unknownReject {
+		if _, ok := app.aliases[unknown]; !ok {
+			return App{}, fmt.Errorf("unknown type behavior `%s` is not a declared type", unknown)
+		}
+	}
+
+	return app, nil
+}
+
+func loadFile(filename string, output any) error {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+
+	if err = json.Unmarshal(content, output); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return nil
+}
+
+func (a App) Types() []model.Type {
+	return a.types
+}
+
+// ResolveType returns the canonical name of a type, applying the unknown type behavior
+func (a App) ResolveType(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) == 0 {
+		return name, nil
+	}
+
+	if canonical, ok := a.aliases[name]; ok {
+		return canonical, nil
+	}
+
+	switch a.unknown {
+	case unknownAccept, "":
+		return name, nil
+	case unknownReject:
+		return "", httpModel.WrapInvalid(fmt.Errorf("commit's type `%s` is unknown, see `/api/types` for allowed types", name))
+	default:
+		return a.aliases[a.unknown], nil
+	}
+}
+
+// ResolveTypes returns canonical names of types used for filtering, unknown types are kept as is
+func (a App) ResolveTypes(names []string) []string {
+	output := make([]string, len(names))
+
+	for index, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if canonical, ok := a.aliases[name]; ok {
+			output[index] = canonical
+		} else {
+			output[index] = name
+		}
+	}
+
+	return output
+}
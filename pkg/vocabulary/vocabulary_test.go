@@ -0,0 +1,131 @@
+package vocabulary
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+func TestNewApp(t *testing.T) {
+	cases := map[string]struct {
+		types   []model.Type
+		unknown string
+		wantErr error
+	}{
+		"default": {
+			defaultTypes,
+			unknownAccept,
+			nil,
+		},
+		"empty name": {
+			[]model.Type{{Color: "red"}},
+			unknownAccept,
+			errors.New("type's name is required"),
+		},
+		"duplicate alias": {
+			[]model.Type{{Name: "feat"}, {Name: "feature", Aliases: []string{"feat"}}},
+			unknownAccept,
+			errors.New("type `feat` is declared by `feat` and `feature`"),
+		},
+		"unknown remap": {
+			defaultTypes,
+			"misc",
+			errors.New("unknown type behavior `misc` is not a declared type"),
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			_, gotErr := newApp(tc.types, tc.unknown)
+
+			failed := false
+
+			if tc.wantErr == nil && gotErr != nil {
+				failed = true
+			} else if tc.wantErr != nil && gotErr == nil {
+				failed = true
+			} else if tc.wantErr != nil && !strings.Contains(gotErr.Error(), tc.wantErr.Error()) {
+				failed = true
+			}
+
+			if failed {
+				t.Errorf("newApp() = `%s`, want `%s`", gotErr, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveType(t *testing.T) {
+	cases := map[string]struct {
+		unknown string
+		input   string
+		want    string
+		wantErr error
+	}{
+		"known": {
+			unknownReject,
+			"feat",
+			"feat",
+			nil,
+		},
+		"alias": {
+			unknownReject,
+			"Feature",
+			"feat",
+			nil,
+		},
+		"accept": {
+			unknownAccept,
+			"fet",
+			"fet",
+			nil,
+		},
+		"reject": {
+			unknownReject,
+			"fet",
+			"",
+			errors.New("commit's type `fet` is unknown"),
+		},
+		"remap": {
+			"chore",
+			"fet",
+			"chore",
+			nil,
+		},
+		"remap alias": {
+			"doc",
+			"fet",
+			"docs",
+			nil,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			instance, err := newApp(defaultTypes, tc.unknown)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, gotErr := instance.ResolveType(tc.input)
+
+			failed := false
+
+			if tc.wantErr == nil && gotErr != nil {
+				failed = true
+			} else if tc.wantErr != nil && gotErr == nil {
+				failed = true
+			} else if tc.wantErr != nil && !strings.Contains(gotErr.Error(), tc.wantErr.Error()) {
+				failed = true
+			} else if got != tc.want {
+				failed = true
+			}
+
+			if failed {
+				t.Errorf("ResolveType() = (`%s`, `%s`), want (`%s`, `%s`)", got, gotErr, tc.want, tc.wantErr)
+			}
+		})
+	}
+}
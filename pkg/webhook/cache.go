@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+)
+
+const webhooksTTL = time.Minute
+
+// webhooks keeps in memory the webhooks, reloaded periodically to catch changes of other instances
+type webhooks struct {
+	expiration time.Time
+	values     []model.Webhook
+	mutex      sync.RWMutex
+}
+
+func (w *webhooks) get() ([]model.Webhook, bool) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if time.Now().After(w.expiration) {
+		return nil, false
+	}
+
+	return w.values, true
+}
+
+func (w *webhooks) set(values []model.Webhook) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.values = values
+	w.expiration = time.Now().Add(webhooksTTL)
+}
+
+func (w *webhooks) expire() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.expiration = time.Time{}
+}
@@ -0,0 +1,259 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ViBiOh/flags"
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/logger"
+	"github.com/ViBiOh/httputils/v4/pkg/request"
+)
+
+const (
+	// SignatureKeyID is the key id of the HTTP signature of deliveries, verifiable with the webhook's secret
+	SignatureKeyID = "herodote"
+
+	eventHeader  = "X-Herodote-Event"
+	commitEvent  = "commit"
+	storeTimeout = time.Second * 10
+)
+
+// Store persists webhooks and their failed deliveries
+type Store interface {
+	ListWebhooks(context.Context) ([]model.Webhook, error)
+	SaveWebhookFailure(context.Context, model.WebhookFailure) error
+}
+
+// Event is the payload delivered to webhooks
+type Event struct {
+	Date   time.Time    `json:"date"`
+	Event  string       `json:"event"`
+	Commit model.Commit `json:"commit"`
+}
+
+type App struct {
+	store    Store
+	webhooks *webhooks
+	queue    chan model.Commit
+	done     chan struct{}
+	sleep    func(context.Context, time.Duration) error
+	attempts uint
+	backoff  time.Duration
+	timeout  time.Duration
+	workers  uint
+}
+
+type Config struct {
+	attempts  *uint
+	backoff   *time.Duration
+	timeout   *time.Duration
+	workers   *uint
+	queueSize *uint
+}
+
+func Flags(fs *flag.FlagSet, prefix string) Config {
+	return Config{
+		attempts:  flags.New("Attempts", "Delivery attempts before keeping it as failed").Prefix(prefix).DocPrefix("webhook").Uint(fs, 5, nil),
+		backoff:   flags.New("Backoff", "Delay before the first retry, doubled on each attempt").Prefix(prefix).DocPrefix("webhook").Duration(fs, time.Second, nil),
+		timeout:   flags.New("Timeout", "Timeout of a delivery attempt").Prefix(prefix).DocPrefix("webhook").Duration(fs, time.Second*10, nil),
+		workers:   flags.New("Workers", "Concurrent deliveries").Prefix(prefix).DocPrefix("webhook").Uint(fs, 4, nil),
+		queueSize: flags.New("QueueSize", "Commits waiting for delivery, commits are not notified when full").Prefix(prefix).DocPrefix("webhook").Uint(fs, 256, nil),
+	}
+}
+
+func New(config Config, store Store) App {
+	app := App{
+		store:    store,
+		webhooks: &webhooks{},
+		queue:    make(chan model.Commit, *config.queueSize),
+		done:     make(chan struct{}),
+		sleep:    sleep,
+		attempts: *config.attempts,
+		backoff:  *config.backoff,
+		timeout:  *config.timeout,
+		workers:  *config.workers,
+	}
+
+	if app.attempts == 0 {
+		app.attempts = 1
+	}
+
+	if app.workers == 0 {
+		app.workers = 1
+	}
+
+	return app
+}
+
+// Start delivers queued commits until Close is called
+func (a App) Start() {
+	var wg sync.WaitGroup
+
+	for i := uint(0); i < a.workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for commit := range a.queue {
+				a.notify(context.Background(), commit)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(a.done)
+}
+
+// Close stops accepting commits and waits for pending deliveries
+func (a App) Close() {
+	close(a.queue)
+	<-a.done
+}
+
+// Notify queues a saved commit for delivery without blocking
+func (a App) Notify(commit model.Commit) {
+	select {
+	case a.queue <- commit:
+	default:
+		logger.WithField("repository", commit.Repository).WithField("hash", commit.Hash).Error("webhook queue is full, commit is not notified")
+	}
+}
+
+// Expire forces the reload of webhooks on next notification
+func (a App) Expire() {
+	a.webhooks.expire()
+}
+
+func (a App) notify(ctx context.Context, commit model.Commit) {
+	list, err := a.list(ctx)
+	if err != nil {
+		logger.Error("list webhooks: %s", err)
+		return
+	}
+
+	var payload []byte
+
+	for _, webhook := range list {
+		if !webhook.Filter.Matches(commit) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = json.Marshal(Event{Event: commitEvent, Date: time.Now(), Commit: commit}); err != nil {
+				logger.Error("marshal event: %s", err)
+				return
+			}
+		}
+
+		attempts, err := a.deliver(ctx, webhook, payload)
+		if err == nil {
+			continue
+		}
+
+		logger.WithField("webhook", webhook.Name).Error("deliver after %d attempts: %s", attempts, err)
+
+		if err = a.saveFailure(ctx, model.WebhookFailure{
+			Webhook:  webhook.Name,
+			Payload:  payload,
+			Error:    err.Error(),
+			Attempts: attempts,
+		}); err != nil {
+			logger.WithField("webhook", webhook.Name).Error("save failure: %s", err)
+		}
+	}
+}
+
+func (a App) list(ctx context.Context) ([]model.Webhook, error) {
+	if list, ok := a.webhooks.get(); ok {
+		return list, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, storeTimeout)
+	defer cancel()
+
+	list, err := a.store.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.webhooks.set(list)
+
+	return list, nil
+}
+
+func (a App) saveFailure(ctx context.Context, failure model.WebhookFailure) error {
+	ctx, cancel := context.WithTimeout(ctx, storeTimeout)
+	defer cancel()
+
+	return a.store.SaveWebhookFailure(ctx, failure)
+}
+
+// deliver posts the payload with retries, it returns the number of attempts made
+func (a App) deliver(ctx context.Context, webhook model.Webhook, payload []byte) (uint, error) {
+	backoff := a.backoff
+
+	for attempt := uint(1); ; attempt++ {
+		err := a.post(ctx, webhook, payload)
+		if err == nil || !retryable(err) || attempt >= a.attempts {
+			return attempt, err
+		}
+
+		if sleepErr := a.sleep(ctx, backoff); sleepErr != nil {
+			return attempt, errors.Join(err, sleepErr)
+		}
+
+		backoff *= 2
+	}
+}
+
+func (a App) post(ctx context.Context, webhook model.Webhook, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	resp, err := request.Post(webhook.URL).
+		Header(eventHeader, commitEvent).
+		ContentJSON().
+		WithSignatureAuthorization(SignatureKeyID, []byte(webhook.Secret)).
+		Send(ctx, io.NopCloser(bytes.NewReader(payload)))
+	if err != nil {
+		return err
+	}
+
+	if err = request.DiscardBody(resp.Body); err != nil {
+		return fmt.Errorf("discard body: %w", err)
+	}
+
+	return nil
+}
+
+// retryable checks if a failed delivery may succeed later, client errors are definitive except timeouts and rate limits
+func retryable(err error) bool {
+	var respErr request.RequestError
+	if !errors.As(err, &respErr) {
+		return true
+	}
+
+	return respErr.StatusCode >= http.StatusInternalServerError || respErr.StatusCode == http.StatusRequestTimeout || respErr.StatusCode == http.StatusTooManyRequests
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
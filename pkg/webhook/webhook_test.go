@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ViBiOh/herodote/pkg/model"
+	"github.com/ViBiOh/httputils/v4/pkg/request"
+)
+
+type stubStore struct {
+	webhooks []model.Webhook
+	failures []model.WebhookFailure
+	mutex    sync.Mutex
+}
+
+func (s *stubStore) ListWebhooks(_ context.Context) ([]model.Webhook, error) {
+	return s.webhooks, nil
+}
+
+func (s *stubStore) SaveWebhookFailure(_ context.Context, failure model.WebhookFailure) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.failures = append(s.failures, failure)
+	return nil
+}
+
+func noSleep(context.Context, time.Duration) error {
+	return nil
+}
+
+func TestNotify(t *testing.T) {
+	const secret = "s3cr3t"
+
+	cases := map[string]struct {
+		statuses     []int
+		filter       model.CommitFilter
+		wantCalls    int32
+		wantFailures int
+	}{
+		"delivered": {
+			[]int{http.StatusNoContent},
+			model.CommitFilter{Repositories: []string{"vibioh/*"}},
+			1,
+			0,
+		},
+		"not matching": {
+			[]int{http.StatusNoContent},
+			model.CommitFilter{Breaking: true},
+			0,
+			0,
+		},
+		"retried": {
+			[]int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK},
+			model.CommitFilter{},
+			3,
+			0,
+		},
+		"definitive": {
+			[]int{http.StatusBadRequest},
+			model.CommitFilter{},
+			1,
+			1,
+		},
+		"dead letter": {
+			[]int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			model.CommitFilter{},
+			3,
+			1,
+		},
+	}
+
+	for intention, tc := range cases {
+		t.Run(intention, func(t *testing.T) {
+			var calls int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+
+				if ok, err := request.ValidateSignature(r, []byte(secret)); !ok || err != nil {
+					t.Errorf("invalid signature: %s", err)
+				}
+
+				if got := r.Header.Get(eventHeader); got != commitEvent {
+					t.Errorf("%s = `%s`, want `%s`", eventHeader, got, commitEvent)
+				}
+
+				w.WriteHeader(tc.statuses[call-1])
+			}))
+			defer server.Close()
+
+			store := &stubStore{webhooks: []model.Webhook{{Name: "test", URL: server.URL + "/hooks/herodote", Secret: secret, Filter: tc.filter}}}
+
+			instance := App{
+				store:    store,
+				webhooks: &webhooks{},
+				sleep:    noSleep,
+				attempts: 3,
+				timeout:  time.Second,
+			}
+
+			instance.notify(context.Background(), model.Commit{Repository: "vibioh/herodote", Hash: "1a2bc34d", Type: "feat"})
+
+			if calls != tc.wantCalls {
+				t.Errorf("notify() calls = %d, want %d", calls, tc.wantCalls)
+			}
+
+			if len(store.failures) != tc.wantFailures {
+				t.Errorf("notify() failures = %d, want %d", len(store.failures), tc.wantFailures)
+			}
+		})
+	}
+}
@@ -1,12 +1,30 @@
 --- clean
 DROP MATERIALIZED VIEW IF EXISTS herodote.filters;
 
+DROP TABLE IF EXISTS herodote.audit;
+DROP TABLE IF EXISTS herodote.deployment;
+DROP TABLE IF EXISTS herodote.private_repository;
+DROP TABLE IF EXISTS herodote.subscription;
+DROP TABLE IF EXISTS herodote.webhook_failure;
+DROP TABLE IF EXISTS herodote.webhook;
+DROP TABLE IF EXISTS herodote.repository_alias;
+DROP TABLE IF EXISTS herodote.token;
+DROP TABLE IF EXISTS herodote.commit_branch;
 DROP TABLE IF EXISTS herodote.commit;
 
 DROP INDEX IF EXISTS words;
 DROP INDEX IF EXISTS commit_repository;
 DROP INDEX IF EXISTS commit_component;
 DROP INDEX IF EXISTS commit_type;
+DROP INDEX IF EXISTS commit_refs;
+DROP INDEX IF EXISTS commit_seq;
+DROP INDEX IF EXISTS commit_tenant;
+DROP INDEX IF EXISTS commit_branch_id;
+DROP INDEX IF EXISTS commit_branch_branch;
+DROP INDEX IF EXISTS audit_date;
+DROP INDEX IF EXISTS audit_repository;
+
+DROP FUNCTION IF EXISTS herodote.audit_append_only;
 
 DROP SCHEMA IF EXISTS herodote;
 
@@ -24,20 +42,142 @@ CREATE TABLE herodote.commit (
   content TEXT NOT NULL,
   date TIMESTAMP WITH TIME ZONE NOT NULL,
   remote TEXT NOT NULL,
-  search_vector TSVECTOR
+  paths TEXT[] NOT NULL DEFAULT '{}',
+  refs TEXT[] NOT NULL DEFAULT '{}',
+  search_vector TSVECTOR,
+  id BIGSERIAL,
+  picked_from TEXT NOT NULL DEFAULT '',
+  tenant TEXT NOT NULL DEFAULT ''
 );
 
 CREATE UNIQUE INDEX commit_id ON herodote.commit(repository, hash);
 CREATE INDEX commit_repository ON herodote.commit(repository);
 CREATE INDEX commit_component ON herodote.commit(component);
 CREATE INDEX commit_type ON herodote.commit(type);
+CREATE INDEX commit_refs ON herodote.commit USING gin(refs);
 CREATE INDEX commit_search ON herodote.commit USING gist(search_vector);
+CREATE UNIQUE INDEX commit_seq ON herodote.commit(id);
+CREATE INDEX commit_tenant ON herodote.commit(tenant);
+
+-- commit_branch
+CREATE TABLE herodote.commit_branch (
+  repository TEXT NOT NULL,
+  hash TEXT NOT NULL,
+  branch TEXT NOT NULL,
+  FOREIGN KEY (repository, hash) REFERENCES herodote.commit(repository, hash) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX commit_branch_id ON herodote.commit_branch(repository, hash, branch);
+CREATE INDEX commit_branch_branch ON herodote.commit_branch(branch);
 
 -- filters
 CREATE MATERIALIZED VIEW herodote.filters (
   kind,
-  value
+  value,
+  repository
 ) AS
-  SELECT DISTINCT 'repository', repository FROM herodote.commit
-  UNION SELECT DISTINCT 'type', type FROM herodote.commit
-  UNION SELECT DISTINCT 'component', component FROM herodote.commit WHERE component IS NOT NULL;
+  SELECT DISTINCT 'repository', repository, repository FROM herodote.commit
+  UNION SELECT DISTINCT 'type', type, repository FROM herodote.commit
+  UNION SELECT DISTINCT 'component', component, repository FROM herodote.commit WHERE component IS NOT NULL
+  UNION SELECT DISTINCT 'tenant', tenant, repository FROM herodote.commit WHERE tenant <> '';
+
+-- token
+CREATE TABLE herodote.token (
+  name TEXT NOT NULL,
+  hash TEXT NOT NULL,
+  patterns TEXT[] NOT NULL,
+  tenant TEXT NOT NULL DEFAULT '',
+  creation_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  last_used TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX token_name ON herodote.token(name);
+CREATE UNIQUE INDEX token_hash ON herodote.token(hash);
+
+-- repository_alias
+CREATE TABLE herodote.repository_alias (
+  alias TEXT NOT NULL,
+  repository TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX repository_alias_alias ON herodote.repository_alias(alias);
+
+-- webhook
+CREATE TABLE herodote.webhook (
+  name TEXT NOT NULL,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  filter JSONB NOT NULL,
+  creation_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX webhook_name ON herodote.webhook(name);
+
+CREATE TABLE herodote.webhook_failure (
+  webhook TEXT NOT NULL REFERENCES herodote.webhook(name) ON DELETE CASCADE,
+  payload JSONB NOT NULL,
+  error TEXT NOT NULL,
+  attempts INTEGER NOT NULL,
+  creation_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX webhook_failure_webhook ON herodote.webhook_failure(webhook, creation_date);
+
+-- subscription
+CREATE TABLE herodote.subscription (
+  id BIGSERIAL PRIMARY KEY,
+  owner TEXT NOT NULL,
+  email TEXT NOT NULL,
+  frequency TEXT NOT NULL,
+  filter JSONB NOT NULL,
+  tenant TEXT NOT NULL DEFAULT '',
+  creation_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  last_sent TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX subscription_owner ON herodote.subscription(owner);
+
+-- deployment
+CREATE TABLE herodote.deployment (
+  id BIGSERIAL PRIMARY KEY,
+  repository TEXT NOT NULL,
+  environment TEXT NOT NULL,
+  hash TEXT NOT NULL,
+  status TEXT NOT NULL,
+  date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX deployment_environment ON herodote.deployment(repository, environment, date);
+
+-- private_repository
+CREATE TABLE herodote.private_repository (
+  repository TEXT NOT NULL,
+  tenant TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX private_repository_repository ON herodote.private_repository(repository);
+
+-- audit
+CREATE TABLE herodote.audit (
+  id BIGSERIAL PRIMARY KEY,
+  date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  actor TEXT NOT NULL,
+  tenant TEXT NOT NULL DEFAULT '',
+  ip TEXT NOT NULL,
+  method TEXT NOT NULL,
+  path TEXT NOT NULL,
+  repository TEXT NOT NULL DEFAULT '',
+  hash TEXT NOT NULL DEFAULT '',
+  status INTEGER NOT NULL
+);
+
+CREATE INDEX audit_date ON herodote.audit(date);
+CREATE INDEX audit_repository ON herodote.audit(repository, date);
+
+CREATE FUNCTION herodote.audit_append_only() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit entries can not be modified';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_append_only BEFORE UPDATE ON herodote.audit FOR EACH ROW EXECUTE FUNCTION herodote.audit_append_only();
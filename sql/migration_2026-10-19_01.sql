@@ -0,0 +1,10 @@
+CREATE TABLE herodote.token (
+  name TEXT NOT NULL,
+  hash TEXT NOT NULL,
+  patterns TEXT[] NOT NULL,
+  creation_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  last_used TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX token_name ON herodote.token(name);
+CREATE UNIQUE INDEX token_hash ON herodote.token(hash);
@@ -0,0 +1,6 @@
+CREATE TABLE herodote.repository_alias (
+  alias TEXT NOT NULL,
+  repository TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX repository_alias_alias ON herodote.repository_alias(alias);
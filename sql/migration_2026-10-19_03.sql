@@ -0,0 +1 @@
+ALTER TABLE herodote.commit ADD COLUMN paths TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,16 @@
+ALTER TABLE herodote.commit ADD COLUMN refs TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX commit_refs ON herodote.commit USING gin(refs);
+
+-- backfill issues and pull requests references, tracker keys are only extracted for new commits
+UPDATE
+  herodote.commit
+SET
+  refs = ARRAY(
+    SELECT DISTINCT
+      match[1]
+    FROM
+      regexp_matches(content, '(?:^|[^[:alnum:]_&])(#[0-9]+)\M', 'g') AS match
+    ORDER BY
+      match[1]
+  );
@@ -0,0 +1,19 @@
+CREATE TABLE herodote.webhook (
+  name TEXT NOT NULL,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  filter JSONB NOT NULL,
+  creation_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX webhook_name ON herodote.webhook(name);
+
+CREATE TABLE herodote.webhook_failure (
+  webhook TEXT NOT NULL REFERENCES herodote.webhook(name) ON DELETE CASCADE,
+  payload JSONB NOT NULL,
+  error TEXT NOT NULL,
+  attempts INTEGER NOT NULL,
+  creation_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX webhook_failure_webhook ON herodote.webhook_failure(webhook, creation_date);
@@ -0,0 +1,11 @@
+CREATE TABLE herodote.subscription (
+  id BIGSERIAL PRIMARY KEY,
+  owner TEXT NOT NULL,
+  email TEXT NOT NULL,
+  frequency TEXT NOT NULL,
+  filter JSONB NOT NULL,
+  creation_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  last_sent TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX subscription_owner ON herodote.subscription(owner);
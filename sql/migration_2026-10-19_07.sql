@@ -0,0 +1,3 @@
+ALTER TABLE herodote.commit ADD COLUMN id BIGSERIAL;
+
+CREATE UNIQUE INDEX commit_seq ON herodote.commit(id);
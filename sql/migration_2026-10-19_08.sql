@@ -0,0 +1,11 @@
+ALTER TABLE herodote.commit ADD COLUMN picked_from TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE herodote.commit_branch (
+  repository TEXT NOT NULL,
+  hash TEXT NOT NULL,
+  branch TEXT NOT NULL,
+  FOREIGN KEY (repository, hash) REFERENCES herodote.commit(repository, hash) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX commit_branch_id ON herodote.commit_branch(repository, hash, branch);
+CREATE INDEX commit_branch_branch ON herodote.commit_branch(branch);
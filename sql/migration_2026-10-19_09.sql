@@ -0,0 +1,10 @@
+CREATE TABLE herodote.deployment (
+  id BIGSERIAL PRIMARY KEY,
+  repository TEXT NOT NULL,
+  environment TEXT NOT NULL,
+  hash TEXT NOT NULL,
+  status TEXT NOT NULL,
+  date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX deployment_environment ON herodote.deployment(repository, environment, date);
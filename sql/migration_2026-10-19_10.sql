@@ -0,0 +1,24 @@
+ALTER TABLE herodote.commit ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE herodote.token ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE herodote.subscription ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX commit_tenant ON herodote.commit(tenant);
+
+CREATE TABLE herodote.private_repository (
+  repository TEXT NOT NULL,
+  tenant TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX private_repository_repository ON herodote.private_repository(repository);
+
+DROP MATERIALIZED VIEW herodote.filters;
+
+CREATE MATERIALIZED VIEW herodote.filters (
+  kind,
+  value,
+  repository
+) AS
+  SELECT DISTINCT 'repository', repository, repository FROM herodote.commit
+  UNION SELECT DISTINCT 'type', type, repository FROM herodote.commit
+  UNION SELECT DISTINCT 'component', component, repository FROM herodote.commit WHERE component IS NOT NULL
+  UNION SELECT DISTINCT 'tenant', tenant, repository FROM herodote.commit WHERE tenant <> '';
@@ -0,0 +1,23 @@
+CREATE TABLE herodote.audit (
+  id BIGSERIAL PRIMARY KEY,
+  date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  actor TEXT NOT NULL,
+  tenant TEXT NOT NULL DEFAULT '',
+  ip TEXT NOT NULL,
+  method TEXT NOT NULL,
+  path TEXT NOT NULL,
+  repository TEXT NOT NULL DEFAULT '',
+  hash TEXT NOT NULL DEFAULT '',
+  status INTEGER NOT NULL
+);
+
+CREATE INDEX audit_date ON herodote.audit(date);
+CREATE INDEX audit_repository ON herodote.audit(repository, date);
+
+CREATE FUNCTION herodote.audit_append_only() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit entries can not be modified';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_append_only BEFORE UPDATE ON herodote.audit FOR EACH ROW EXECUTE FUNCTION herodote.audit_append_only();